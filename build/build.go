@@ -1,6 +1,10 @@
 package build
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/build"
@@ -15,9 +19,11 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/gopherjs/gopherjs/compiler"
@@ -73,11 +79,18 @@ func Import(path string, mode build.ImportMode, installSuffix string, buildTags
 }
 
 func importWithSrcDir(path string, srcDir string, mode build.ImportMode, installSuffix string, buildTags []string) (*PackageData, error) {
-	bctx := NewBuildContext(installSuffix, buildTags)
+	return importWithContext(*NewBuildContext(installSuffix, buildTags), path, srcDir, mode, false)
+}
+
+// importWithContext is like importWithSrcDir, but uses a copy of the given
+// build context, including its file system hooks, to locate the package. If
+// allowCgo is set, files using cgo are added to GoFiles instead of failing.
+func importWithContext(bctx build.Context, path string, srcDir string, mode build.ImportMode, allowCgo bool) (*PackageData, error) {
 	switch path {
 	case "syscall":
 		// syscall needs to use a typical GOARCH like amd64 to pick up definitions for _Socklen, BpfInsn, IFNAMSIZ, Timeval, BpfStat, SYS_FCNTL, Flock_t, etc.
 		bctx.GOARCH = runtime.GOARCH
+		installSuffix := bctx.InstallSuffix
 		bctx.InstallSuffix = "js"
 		if installSuffix != "" {
 			bctx.InstallSuffix += "_" + installSuffix
@@ -93,6 +106,14 @@ func importWithSrcDir(path string, srcDir string, mode build.ImportMode, install
 	if err != nil {
 		return nil, err
 	}
+	if build.IsLocalImport(pkg.ImportPath) {
+		// The package is outside of the GOPATH workspaces and was imported by
+		// a path relative to srcDir, which doesn't identify it. Name it after
+		// its directory instead, like the go command does, so that packages
+		// imported by the same relative path from different directories are
+		// built separately.
+		pkg.ImportPath = localImportPath(pkg.Dir)
+	}
 
 	// TODO: Resolve issue #415 and remove this temporary workaround.
 	if strings.HasSuffix(pkg.ImportPath, "/vendor/github.com/gopherjs/gopherjs/js") {
@@ -115,7 +136,11 @@ func importWithSrcDir(path string, srcDir string, mode build.ImportMode, install
 	}
 
 	if len(pkg.CgoFiles) > 0 {
-		return nil, &ImportCError{path}
+		if !allowCgo {
+			return nil, &ImportCError{path}
+		}
+		pkg.GoFiles = append(pkg.GoFiles, pkg.CgoFiles...)
+		pkg.CgoFiles = nil
 	}
 
 	if pkg.IsCommand() {
@@ -131,7 +156,7 @@ func importWithSrcDir(path string, srcDir string, mode build.ImportMode, install
 		}
 	}
 
-	jsFiles, err := jsFilesFromDir(pkg.Dir)
+	jsFiles, err := jsFilesFromDir(&bctx, pkg.Dir)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +164,17 @@ func importWithSrcDir(path string, srcDir string, mode build.ImportMode, install
 	return &PackageData{Package: pkg, JSFiles: jsFiles}, nil
 }
 
+// localImportPath returns the import path that the go command gives to the
+// package in dir outside of the GOPATH workspaces, like "_/home/gopher/util".
+func localImportPath(dir string) string {
+	return path.Join("_", strings.Map(func(r rune) rune {
+		if !unicode.IsGraphic(r) || unicode.IsSpace(r) || strings.ContainsRune("!\"#$%&'()*,:;<=>?[\\]^{|}`\uFFFD", r) {
+			return '_'
+		}
+		return r
+	}, filepath.ToSlash(dir)))
+}
+
 // excludeExecutable excludes all executable implementation .go files.
 // They have "executable_" prefix.
 func excludeExecutable(goFiles []string) []string {
@@ -170,12 +206,13 @@ Outer:
 // ImportDir is like Import but processes the Go package found in the named
 // directory.
 func ImportDir(dir string, mode build.ImportMode, installSuffix string, buildTags []string) (*PackageData, error) {
-	pkg, err := NewBuildContext(installSuffix, buildTags).ImportDir(dir, mode)
+	bctx := NewBuildContext(installSuffix, buildTags)
+	pkg, err := bctx.ImportDir(dir, mode)
 	if err != nil {
 		return nil, err
 	}
 
-	jsFiles, err := jsFilesFromDir(pkg.Dir)
+	jsFiles, err := jsFilesFromDir(bctx, pkg.Dir)
 	if err != nil {
 		return nil, err
 	}
@@ -194,7 +231,7 @@ func ImportDir(dir string, mode build.ImportMode, installSuffix string, buildTag
 // as an existing file from the standard library). For all identifiers that exist
 // in the original AND the overrides, the original identifier in the AST gets
 // replaced by `_`. New identifiers that don't exist in original package get added.
-func parseAndAugment(pkg *build.Package, isTest bool, fileSet *token.FileSet) ([]*ast.File, error) {
+func parseAndAugment(bctx *build.Context, pkg *build.Package, isTest bool, fileSet *token.FileSet) ([]*ast.File, error) {
 	var files []*ast.File
 	replacedDeclNames := make(map[string]bool)
 	funcName := func(d *ast.FuncDecl) string {
@@ -297,21 +334,19 @@ func parseAndAugment(pkg *build.Package, isTest bool, fileSet *token.FileSet) ([
 	delete(replacedDeclNames, "init")
 
 	var errList compiler.ErrorList
+	placeholderDeclared := false
 	for _, name := range pkg.GoFiles {
 		if !filepath.IsAbs(name) {
 			name = filepath.Join(pkg.Dir, name)
 		}
-		r, err := os.Open(name)
+		r, err := openFile(bctx, name)
 		if err != nil {
 			return nil, err
 		}
-		file, err := parser.ParseFile(fileSet, name, r, parser.ParseComments)
+		file, err := parser.ParseFile(fileSet, name, r, parser.ParseComments|parser.AllErrors)
 		r.Close()
 		if err != nil {
 			if list, isList := err.(scanner.ErrorList); isList {
-				if len(list) > 10 {
-					list = append(list[:10], &scanner.Error{Pos: list[9].Pos, Msg: "too many errors"})
-				}
 				for _, entry := range list {
 					errList = append(errList, entry)
 				}
@@ -320,6 +355,19 @@ func parseAndAugment(pkg *build.Package, isTest bool, fileSet *token.FileSet) ([
 			errList = append(errList, err)
 			continue
 		}
+		if importsC(file) {
+			// Only packages imported with options.AllowUnsupported have cgo files here.
+			src, err := readFile(bctx, name)
+			if err != nil {
+				return nil, err
+			}
+			file, err = stubCgoFile(fileSet, file, src, pkg.ImportPath, !placeholderDeclared)
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+			placeholderDeclared = true
+		}
 
 		switch pkg.ImportPath {
 		case "crypto/rand", "encoding/gob", "encoding/json", "expvar", "go/token", "log", "math/big", "math/rand", "regexp", "testing", "time":
@@ -374,12 +422,187 @@ type Options struct {
 	GOPATH         string
 	Verbose        bool
 	Quiet          bool
+	Silent         bool
 	Watch          bool
 	CreateMapFile  bool
 	MapToLocalDisk bool
 	Minify         bool
 	Color          bool
 	BuildTags      []string
+	IgnoreVendor   bool
+	DumpTypes      bool
+	AllErrors      bool
+	PrependFile    string
+	AppendFile     string
+	Embed          []string
+	Strict         bool
+	PkgDir         string
+	Target         string
+	ForceRebuild   bool
+	MaxFuncSize    int
+	DryRun         bool
+	EmitMetadata   bool
+	Split          bool
+	Verify         bool
+	OptLevel       int
+
+	// ErrorFormat is how errors and warnings are printed: "" or "text" for
+	// lines of text, or "json" for JSON arrays of objects with their file,
+	// line, col, message and severity, for editors.
+	ErrorFormat string
+
+	// AllowUnsupported compiles packages using cgo, which is not supported,
+	// with the functions that use it replaced by stubs that panic when called.
+	AllowUnsupported bool
+
+	// Lang is the version of the Go language, like "go1.8", that packages
+	// outside of GOROOT are written for, see CheckLang. It caps the language
+	// version: newer versions than the compiler implements are rejected, and
+	// below go1.9 alias declarations are errors. Other language changes of
+	// earlier versions are compiled alike.
+	Lang string
+
+	// DocIndex writes the exported symbols of library packages with their
+	// doc comments as JSON next to their archives, with the extension
+	// replaced by .index.json.
+	DocIndex bool
+
+	// BuildID starts the output of commands with a comment holding a hash
+	// of the program, which is reproduced by building the same sources with
+	// the same options.
+	BuildID bool
+
+	// TraceImports prints the tree of imports of the packages that are built.
+	TraceImports bool
+
+	// StdlibCache is a directory of prebuilt archives of the standard library,
+	// which are loaded instead of compiling the packages of GOROOT. It is laid
+	// out like PkgDir, below a directory named after compiler.Version, so it
+	// can be populated with "gopherjs install --pkgdir=DIR/VERSION" and the
+	// same build tags.
+	StdlibCache string
+
+	// Race instruments the packages outside of GOROOT to report accesses of
+	// package level variables by different goroutines without
+	// synchronization in between, see the race argument of compiler.Compile.
+	Race bool
+
+	// BuildVCS sets the variable vcsRevision of main packages, which must be
+	// declared without a value, to the git commit of their directory, or to
+	// "" outside of a git repository. Main packages without the variable,
+	// built for tests, or synthesized for libraries and entry points are left
+	// unchanged. Commands are always rebuilt, since the revision changes
+	// without their sources.
+	BuildVCS bool
+
+	// StackTrace selects what is printed for an unrecovered panic under
+	// Node.js besides the panic value: "full", the default, prints the
+	// JavaScript stack, "go" only its frames in Go source files, which
+	// requires source maps, and "none" nothing.
+	StackTrace string
+
+	// Format is the kind of output written for commands: "" for a single
+	// script, or "cjs" for a directory of CommonJS modules: prelude.js, one
+	// for each package, which requires prelude.js and the modules of its
+	// imports, and index.js, which requires all of them and starts the
+	// program when the directory is required. Source maps are not written
+	// for "cjs".
+	Format string
+
+	// BundleReport, if set, is the name of an HTML file to which the
+	// breakdown of the output of commands by package is written.
+	BundleReport string
+
+	// OpenFile, ReadDir and IsDir replace the local file system when reading
+	// package sources, if set. They have the semantics of the go/build.Context
+	// hooks of the same names.
+	OpenFile func(path string) (io.ReadCloser, error)
+	ReadDir  func(dir string) ([]os.FileInfo, error)
+	IsDir    func(path string) bool
+
+	// Importer, if set, is consulted before an imported package is looked up
+	// and built from source. It is called with the import path and the
+	// directory of the importing package, and returns the archive of the
+	// package, or nil to fall back to building it. Archives it returns must
+	// be complete, including all of their own dependencies, and the same
+	// archive must be returned for the same import path within a Session.
+	Importer func(path, srcDir string) (*compiler.Archive, error)
+
+	// SourceArchive, if set, is searched for packages before the GOPATH workspaces.
+	SourceArchive *SourceArchive
+
+	// Overlay, if set, maps the absolute paths of source files to the paths of
+	// the files read instead, or to "" for files treated as absent, like the
+	// -overlay flag of go build. See ReadOverlay.
+	Overlay map[string]string
+}
+
+// ErrorList returns the entries of err if it is a compiler.ErrorList, or err
+// alone, to be reported. Unless o.AllErrors is set, they are limited to the
+// first 10 errors.
+func (o *Options) ErrorList(err error) compiler.ErrorList {
+	list, ok := err.(compiler.ErrorList)
+	if !ok {
+		list = compiler.ErrorList{err}
+	}
+	if !o.AllErrors {
+		list = list.Truncate(10)
+	}
+	return list
+}
+
+// PrintWarning prints the warning err to Stderr, unless o.Quiet is set, like
+// "file:line:col: warning: message" if it is a types.Error, or as a JSON array
+// of one object with the severity "warning" with o.ErrorFormat "json".
+func (o *Options) PrintWarning(err error) {
+	if o.Quiet {
+		return
+	}
+	if o.ErrorFormat == "json" {
+		printJSON(compiler.ErrorList{err}, "warning")
+		return
+	}
+	if e, ok := err.(types.Error); ok {
+		fmt.Fprintf(os.Stderr, "%s: warning: %s\n", e.Fset.Position(e.Pos), e.Msg)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "warning: %s\n", err)
+}
+
+// PrintJSONErrors prints the errors of list to Stderr as a JSON array of
+// objects with their file, line, col, message and the severity "error", for
+// o.ErrorFormat "json". Errors without a position, like failures to find a
+// package, have an empty file and zero line and column.
+func (o *Options) PrintJSONErrors(list compiler.ErrorList) {
+	printJSON(list, "error")
+}
+
+// jsonError is an error or warning as printed with Options.ErrorFormat "json".
+type jsonError struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Col      int    `json:"col"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+// printJSON prints the entries of list with severity as a JSON array of
+// jsonError to Stderr.
+func printJSON(list compiler.ErrorList, severity string) {
+	entries := []jsonError{}
+	for _, entry := range list {
+		e := jsonError{Message: entry.Error(), Severity: severity}
+		switch entry := entry.(type) {
+		case *scanner.Error:
+			e.File, e.Line, e.Col, e.Message = entry.Pos.Filename, entry.Pos.Line, entry.Pos.Column, entry.Msg
+		case types.Error:
+			pos := entry.Fset.Position(entry.Pos)
+			e.File, e.Line, e.Col, e.Message = pos.Filename, pos.Line, pos.Column, entry.Msg
+		}
+		entries = append(entries, e)
+	}
+	out, _ := json.Marshal(entries)
+	fmt.Fprintf(os.Stderr, "%s\n", out)
 }
 
 func (o *Options) PrintError(format string, a ...interface{}) {
@@ -390,18 +613,39 @@ func (o *Options) PrintError(format string, a ...interface{}) {
 }
 
 func (o *Options) PrintSuccess(format string, a ...interface{}) {
+	if o.Silent {
+		return
+	}
 	if o.Color {
 		format = "\x1B[32m" + format + "\x1B[39m"
 	}
 	fmt.Fprintf(os.Stderr, format, a...)
 }
 
+// PrintInfo prints informational output to stdout, unless o.Silent is set.
+func (o *Options) PrintInfo(format string, a ...interface{}) {
+	if o.Silent {
+		return
+	}
+	fmt.Printf(format, a...)
+}
+
 type PackageData struct {
 	*build.Package
 	JSFiles    []string
 	IsTest     bool // IsTest is true if the package is being built for running tests.
 	SrcModTime time.Time
 	UpToDate   bool
+
+	// Synthetic is true for main packages generated to run other packages,
+	// like those of BuildLibrary and BuildEntry.
+	Synthetic bool
+
+	// CoverMode, if set, makes BuildPackage instrument the Go files of the
+	// package that are not test files for coverage in this mode, "set" or
+	// "count", and record them in CoverFiles.
+	CoverMode  string
+	CoverFiles []CoverFile
 }
 
 type Session struct {
@@ -409,6 +653,11 @@ type Session struct {
 	Archives map[string]*compiler.Archive
 	Types    map[string]*types.Package
 	Watcher  *fsnotify.Watcher
+
+	// importStack holds the packages being built, from the outermost, and
+	// tracedImports the imports printed so far, for options.TraceImports.
+	importStack   []string
+	tracedImports map[[2]string]bool
 }
 
 func NewSession(options *Options) *Session {
@@ -419,6 +668,10 @@ func NewSession(options *Options) *Session {
 		options.GOPATH = build.Default.GOPATH
 	}
 	options.Verbose = options.Verbose || options.Watch
+	if options.Silent {
+		options.Verbose = false
+		options.Quiet = true
+	}
 
 	s := &Session{
 		options:  options,
@@ -428,7 +681,7 @@ func NewSession(options *Options) *Session {
 	if options.Watch {
 		if out, err := exec.Command("ulimit", "-n").Output(); err == nil {
 			if n, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil && n < 1024 {
-				fmt.Printf("Warning: The maximum number of open file descriptors is very low (%d). Change it with 'ulimit -n 8192'.\n", n)
+				options.PrintWarning(fmt.Errorf("the maximum number of open file descriptors is very low (%d), change it with 'ulimit -n 8192'", n))
 			}
 		}
 
@@ -442,22 +695,60 @@ func NewSession(options *Options) *Session {
 }
 
 func (s *Session) InstallSuffix() string {
+	var suffixes []string
 	if s.options.Minify {
-		return "min"
+		suffixes = append(suffixes, "min")
+	}
+	if s.options.OptLevel > 0 {
+		// Archives compiled with inlining must not be mixed with others.
+		suffixes = append(suffixes, fmt.Sprintf("opt%d", s.options.OptLevel))
 	}
-	return ""
+	if s.options.Race {
+		suffixes = append(suffixes, "race")
+	}
+	return strings.Join(suffixes, "_")
+}
+
+// buildContext returns the build context used by s to locate and read packages.
+func (s *Session) buildContext() *build.Context {
+	bctx := NewBuildContext(s.InstallSuffix(), s.options.BuildTags)
+	bctx.OpenFile = s.options.OpenFile
+	bctx.ReadDir = s.options.ReadDir
+	bctx.IsDir = s.options.IsDir
+	if s.options.SourceArchive != nil {
+		s.options.SourceArchive.mount(bctx)
+	}
+	if s.options.Overlay != nil {
+		mountOverlay(bctx, s.options.Overlay)
+	}
+	return bctx
+}
+
+// Import is like the package level Import, but locates the package through the
+// build context of the session, which includes options.SourceArchive, the
+// file system hooks of options and options.IgnoreVendor.
+func (s *Session) Import(path string, mode build.ImportMode) (*PackageData, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		wd = ""
+	}
+	if s.options.IgnoreVendor {
+		mode |= build.IgnoreVendor
+	}
+	return importWithContext(*s.buildContext(), path, wd, mode, s.options.AllowUnsupported)
 }
 
 func (s *Session) BuildDir(packagePath string, importPath string, pkgObj string) error {
 	if s.Watcher != nil {
 		s.Watcher.Add(packagePath)
 	}
-	buildPkg, err := NewBuildContext(s.InstallSuffix(), s.options.BuildTags).ImportDir(packagePath, 0)
+	bctx := s.buildContext()
+	buildPkg, err := bctx.ImportDir(packagePath, 0)
 	if err != nil {
 		return err
 	}
 	pkg := &PackageData{Package: buildPkg}
-	jsFiles, err := jsFilesFromDir(pkg.Dir)
+	jsFiles, err := jsFilesFromDir(bctx, pkg.Dir)
 	if err != nil {
 		return err
 	}
@@ -469,7 +760,7 @@ func (s *Session) BuildDir(packagePath string, importPath string, pkgObj string)
 	if pkgObj == "" {
 		pkgObj = filepath.Base(packagePath) + ".js"
 	}
-	if pkg.IsCommand() && !pkg.UpToDate {
+	if pkg.IsCommand() && !pkg.UpToDate && !s.options.DryRun {
 		if err := s.WriteCommandPackage(archive, pkgObj); err != nil {
 			return err
 		}
@@ -498,6 +789,9 @@ func (s *Session) BuildFiles(filenames []string, pkgObj string, packagePath stri
 	if err != nil {
 		return err
 	}
+	if s.options.DryRun {
+		return nil
+	}
 	if s.Types["main"].Name() != "main" {
 		return fmt.Errorf("cannot build/run non-main package")
 	}
@@ -510,7 +804,22 @@ func (s *Session) BuildImportPath(path string) (*compiler.Archive, error) {
 }
 
 func (s *Session) buildImportPathWithSrcDir(path string, srcDir string) (*PackageData, *compiler.Archive, error) {
-	pkg, err := importWithSrcDir(path, srcDir, 0, s.InstallSuffix(), s.options.BuildTags)
+	if s.options.Importer != nil {
+		archive, err := s.options.Importer(path, srcDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if archive != nil {
+			s.Archives[archive.ImportPath] = archive
+			return &PackageData{Package: &build.Package{ImportPath: archive.ImportPath}}, archive, nil
+		}
+	}
+
+	var mode build.ImportMode
+	if s.options.IgnoreVendor {
+		mode |= build.IgnoreVendor
+	}
+	pkg, err := importWithContext(*s.buildContext(), path, srcDir, mode, s.options.AllowUnsupported)
 	if s.Watcher != nil && pkg != nil { // add watch even on error
 		s.Watcher.Add(pkg.Dir)
 	}
@@ -527,9 +836,29 @@ func (s *Session) buildImportPathWithSrcDir(path string, srcDir string) (*Packag
 }
 
 func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
+	if s.options.TraceImports {
+		s.traceImport(pkg.ImportPath)
+		s.importStack = append(s.importStack, pkg.ImportPath)
+		defer func() { s.importStack = s.importStack[:len(s.importStack)-1] }()
+	}
 	if archive, ok := s.Archives[pkg.ImportPath]; ok {
 		return archive, nil
 	}
+	bctx := s.buildContext()
+
+	if s.options.PkgDir != "" && pkg.PkgObj != "" && !pkg.IsCommand() {
+		// Load and store package objects in PkgDir instead of the usual locations, like "go build -pkgdir".
+		pkg.PkgObj = filepath.Join(s.options.PkgDir, s.InstallSuffix(), filepath.FromSlash(pkg.ImportPath)+".a")
+	}
+	if name := s.stdlibCacheObj(pkg); name != "" {
+		return s.loadStdlibCache(pkg, name)
+	}
+	if a := s.options.SourceArchive; a != nil {
+		if _, ok := a.rel(pkg.PkgObj); ok {
+			// Package objects can't be stored inside of the source archive.
+			pkg.PkgObj = ""
+		}
+	}
 
 	if pkg.PkgObj != "" {
 		var fileInfo os.FileInfo
@@ -576,7 +905,7 @@ func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
 		}
 
 		for _, name := range append(pkg.GoFiles, pkg.JSFiles...) {
-			fileInfo, err := os.Stat(filepath.Join(pkg.Dir, name))
+			fileInfo, err := statFile(bctx, pkg.Dir, name)
 			if err != nil {
 				return nil, err
 			}
@@ -585,8 +914,35 @@ func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
 			}
 		}
 
+		if pkg.IsCommand() {
+			assets, err := s.embeddedAssets(bctx, pkg)
+			if err != nil {
+				return nil, err
+			}
+			for _, asset := range assets {
+				for _, name := range asset.files {
+					fileInfo, err := statFile(bctx, filepath.Dir(name), filepath.Base(name))
+					if err != nil {
+						return nil, err
+					}
+					if fileInfo.ModTime().After(pkg.SrcModTime) {
+						pkg.SrcModTime = fileInfo.ModTime()
+					}
+				}
+			}
+		}
+
 		pkgObjFileInfo, err := os.Stat(pkg.PkgObj)
-		if err == nil && !pkg.SrcModTime.After(pkgObjFileInfo.ModTime()) {
+		upToDate := err == nil && !s.options.ForceRebuild && !pkg.SrcModTime.After(pkgObjFileInfo.ModTime()) && !(s.options.BuildVCS && pkg.IsCommand())
+		if upToDate && s.options.DocIndex && !pkg.IsCommand() {
+			_, err := os.Stat(docIndexPath(pkg.PkgObj))
+			upToDate = err == nil
+		}
+		if s.options.DryRun {
+			s.reportDryRun(pkg, upToDate)
+			return nil, nil
+		}
+		if upToDate {
 			// package object is up to date, load from disk if library
 			pkg.UpToDate = true
 			if pkg.IsCommand() {
@@ -610,10 +966,48 @@ func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
 	}
 
 	fileSet := token.NewFileSet()
-	files, err := parseAndAugment(pkg.Package, pkg.IsTest, fileSet)
+	files, err := parseAndAugment(bctx, pkg.Package, pkg.IsTest, fileSet)
 	if err != nil {
 		return nil, err
 	}
+	if !pkg.Goroot {
+		if err := checkLangFeatures(s.options.Lang, files, fileSet); err != nil {
+			return nil, err
+		}
+	}
+	if s.options.DryRun {
+		// Without a package object there is no staleness check that walks
+		// the imports, so do it here.
+		for _, file := range files {
+			for _, spec := range file.Imports {
+				path, _ := strconv.Unquote(spec.Path.Value)
+				if path == "unsafe" {
+					continue
+				}
+				if _, _, err := s.buildImportPathWithSrcDir(path, pkg.Dir); err != nil {
+					return nil, err
+				}
+			}
+		}
+		s.reportDryRun(pkg, false)
+		return nil, nil
+	}
+	if pkg.IsCommand() {
+		if err := s.embedAssets(bctx, pkg, files); err != nil {
+			return nil, err
+		}
+		if s.options.BuildVCS && !pkg.IsTest && !pkg.Synthetic {
+			if err := stampVCS(pkg, files); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if pkg.CoverMode != "" {
+		pkg.CoverFiles, err = instrumentCover(pkg, files, fileSet, pkg.CoverMode)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	localImportPathCache := make(map[string]*compiler.Archive)
 	importContext := &compiler.ImportContext{
@@ -630,13 +1024,13 @@ func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
 			return archive, nil
 		},
 	}
-	archive, err := compiler.Compile(pkg.ImportPath, files, fileSet, importContext, s.options.Minify)
+	archive, err := compiler.Compile(pkg.ImportPath, files, fileSet, importContext, s.options.Minify, s.options.OptLevel, s.options.Race && !pkg.Goroot)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, jsFile := range pkg.JSFiles {
-		code, err := ioutil.ReadFile(filepath.Join(pkg.Dir, jsFile))
+		code, err := readFile(bctx, filepath.Join(pkg.Dir, jsFile))
 		if err != nil {
 			return nil, err
 		}
@@ -645,9 +1039,17 @@ func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
 		archive.IncJSCode = append(archive.IncJSCode, []byte("\n\t}).call($global);\n")...)
 	}
 
+	if s.options.MaxFuncSize > 0 {
+		for _, warning := range largeFunctions(s.Types[pkg.ImportPath], archive, files, fileSet, s.options.MaxFuncSize) {
+			s.options.PrintWarning(warning)
+		}
+	}
 	if s.options.Verbose {
 		fmt.Println(pkg.ImportPath)
 	}
+	if s.options.DumpTypes {
+		dumpTypes(os.Stderr, s.Types[pkg.ImportPath])
+	}
 
 	s.Archives[pkg.ImportPath] = archive
 
@@ -655,11 +1057,15 @@ func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
 		return archive, nil
 	}
 
-	if err := s.writeLibraryPackage(archive, pkg.PkgObj); err != nil {
+	var index *packageDocIndex
+	if s.options.DocIndex {
+		index = newDocIndex(pkg.ImportPath, files, fileSet)
+	}
+	if err := s.writeLibraryPackage(archive, index, pkg.PkgObj); err != nil {
 		if strings.HasPrefix(pkg.PkgObj, s.options.GOROOT) {
 			// fall back to first GOPATH workspace
 			firstGopathWorkspace := filepath.SplitList(s.options.GOPATH)[0]
-			if err := s.writeLibraryPackage(archive, filepath.Join(firstGopathWorkspace, pkg.PkgObj[len(s.options.GOROOT):])); err != nil {
+			if err := s.writeLibraryPackage(archive, index, filepath.Join(firstGopathWorkspace, pkg.PkgObj[len(s.options.GOROOT):])); err != nil {
 				return nil, err
 			}
 			return archive, nil
@@ -670,21 +1076,326 @@ func (s *Session) BuildPackage(pkg *PackageData) (*compiler.Archive, error) {
 	return archive, nil
 }
 
-func (s *Session) writeLibraryPackage(archive *compiler.Archive, pkgObj string) error {
+// langMinor is the minor version of the Go language implemented by the
+// compiler and the go/types it is built with, see compiler/version_check.go.
+const langMinor = 9
+
+// CheckLang returns an error unless lang, a Go language version like "go1.8"
+// for Options.Lang, is empty or can be compiled.
+func CheckLang(lang string) error {
+	_, err := langVersion(lang)
+	return err
+}
+
+// langVersion returns the minor version of the Go language version lang, or
+// langMinor if lang is empty.
+func langVersion(lang string) (int, error) {
+	if lang == "" {
+		return langMinor, nil
+	}
+	minor, err := strconv.Atoi(strings.TrimPrefix(lang, "go1."))
+	if !strings.HasPrefix(lang, "go1.") || err != nil || minor < 0 {
+		return 0, fmt.Errorf("invalid Go language version %q, must be like go1.%d", lang, langMinor)
+	}
+	if minor > langMinor {
+		return 0, fmt.Errorf("Go language version %s is not supported, GopherJS %s implements up to go1.%d", lang, compiler.Version, langMinor)
+	}
+	return minor, nil
+}
+
+// checkLangFeatures returns an error for the declarations of files that the
+// Go language version lang doesn't have. Only alias declarations, added in
+// go1.9, are checked; other changes of earlier versions are compiled alike.
+func checkLangFeatures(lang string, files []*ast.File, fileSet *token.FileSet) error {
+	minor, err := langVersion(lang)
+	if err != nil || minor >= 9 {
+		return err
+	}
+	var errList compiler.ErrorList
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if typeSpec := spec.(*ast.TypeSpec); typeSpec.Assign.IsValid() {
+					errList = append(errList, types.Error{Fset: fileSet, Pos: typeSpec.Assign, Msg: fmt.Sprintf("type aliases require go1.9 or later (-lang was set to %s)", lang)})
+				}
+			}
+		}
+	}
+	if errList != nil {
+		return errList
+	}
+	return nil
+}
+
+// traceImport prints the package path, which is about to be built, indented
+// below the package importing it, for options.TraceImports. Packages that were
+// built before are marked, since their imports are not printed again.
+func (s *Session) traceImport(path string) {
+	var importer string
+	if len(s.importStack) != 0 {
+		importer = s.importStack[len(s.importStack)-1]
+	}
+	if s.tracedImports == nil {
+		s.tracedImports = make(map[[2]string]bool)
+	}
+	edge := [2]string{importer, path}
+	if s.tracedImports[edge] {
+		return // The staleness check and the compiler both import packages.
+	}
+	s.tracedImports[edge] = true
+	note := ""
+	if _, ok := s.Archives[path]; ok {
+		note = " (see above)"
+	}
+	fmt.Fprintf(os.Stderr, "%s%s%s\n", strings.Repeat("  ", len(s.importStack)), path, note)
+}
+
+// reportDryRun prints whether pkg would be loaded from its package object or
+// compiled, and records it as built so that it is reported only once.
+func (s *Session) reportDryRun(pkg *PackageData, upToDate bool) {
+	status := "stale"
+	if upToDate {
+		status = "cached"
+	}
+	fmt.Printf("%s\t%s\n", status, pkg.ImportPath)
+	s.Archives[pkg.ImportPath] = nil
+}
+
+// largeFunctions returns warnings for the functions and methods declared in files
+// whose generated code is larger than limit bytes. JavaScript engines may refuse
+// to run such functions, e.g. V8 does not optimize functions with more than
+// 64KB of bytecode.
+func largeFunctions(typesPkg *types.Package, archive *compiler.Archive, files []*ast.File, fileSet *token.FileSet, limit int) []types.Error {
+	sizes := make(map[string]int)
+	for _, d := range archive.Declarations {
+		if d.FullName != "" {
+			sizes[d.FullName] = len(d.DeclCode)
+		}
+	}
+
+	var warnings []types.Error
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fun, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			o := lookupFunc(typesPkg, fun)
+			if o == nil {
+				continue
+			}
+			if size := sizes[o.FullName()]; size > limit {
+				warnings = append(warnings, types.Error{Fset: fileSet, Pos: fun.Pos(), Msg: fmt.Sprintf("generated code for %s is %d bytes, which may be too large for JavaScript engines", o.FullName(), size)})
+			}
+		}
+	}
+	return warnings
+}
+
+// lookupFunc returns the function or method declared by fun in typesPkg, or nil.
+func lookupFunc(typesPkg *types.Package, fun *ast.FuncDecl) *types.Func {
+	if fun.Recv == nil || len(fun.Recv.List) == 0 {
+		o, _ := typesPkg.Scope().Lookup(fun.Name.Name).(*types.Func)
+		return o
+	}
+	recv := fun.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	ident, ok := recv.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	typeName, ok := typesPkg.Scope().Lookup(ident.Name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	named, ok := typeName.Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < named.NumMethods(); i++ {
+		if m := named.Method(i); m.Name() == fun.Name.Name {
+			return m
+		}
+	}
+	return nil
+}
+
+// embeddedAsset is a package level variable to be initialized with the
+// contents of files, as requested by options.Embed.
+type embeddedAsset struct {
+	varName string
+	files   []string
+}
+
+// embeddedAssets parses options.Embed, which has entries of the form "glob=varname".
+// Relative patterns are matched in the package directory, which is listed
+// through the hooks of bctx.
+func (s *Session) embeddedAssets(bctx *build.Context, pkg *PackageData) ([]embeddedAsset, error) {
+	var assets []embeddedAsset
+	for _, embed := range s.options.Embed {
+		i := strings.LastIndex(embed, "=")
+		if i <= 0 || i == len(embed)-1 {
+			return nil, fmt.Errorf("invalid embed %q, must be of the form glob=varname", embed)
+		}
+		pattern, varName := embed[:i], embed[i+1:]
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(pkg.Dir, pattern)
+		}
+		files, err := glob(bctx, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("embed %q: no matching files found", embed)
+		}
+		assets = append(assets, embeddedAsset{varName: varName, files: files})
+	}
+	return assets, nil
+}
+
+// embedAssets sets the initial value of each package level variable named by
+// options.Embed to the base64 encoded contents of the matching files, concatenated
+// in lexical order. The variables must be declared without an initial value.
+// The files are read through the hooks of bctx.
+func (s *Session) embedAssets(bctx *build.Context, pkg *PackageData, files []*ast.File) error {
+	assets, err := s.embeddedAssets(bctx, pkg)
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		var content []byte
+		for _, name := range asset.files {
+			data, err := readFile(bctx, name)
+			if err != nil {
+				return err
+			}
+			content = append(content, data...)
+		}
+
+		spec := findVarSpec(files, asset.varName)
+		if spec == nil {
+			return fmt.Errorf("embed: package %s has no package level variable %s", pkg.ImportPath, asset.varName)
+		}
+		if len(spec.Names) != 1 || len(spec.Values) != 0 {
+			return fmt.Errorf("embed: variable %s must be declared on its own and without a value", asset.varName)
+		}
+		spec.Values = []ast.Expr{&ast.BasicLit{ValuePos: spec.Names[0].End(), Kind: token.STRING, Value: strconv.Quote(base64.StdEncoding.EncodeToString(content))}}
+	}
+	return nil
+}
+
+// findVarSpec returns the spec declaring the package level variable name, or nil.
+func findVarSpec(files []*ast.File, name string) *ast.ValueSpec {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for _, ident := range valueSpec.Names {
+					if ident.Name == name {
+						return valueSpec
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dumpTypes writes the resolved types of the package level declarations of pkg to w.
+func dumpTypes(w io.Writer, pkg *types.Package) {
+	fmt.Fprintf(w, "package %s (%s)\n", pkg.Path(), pkg.Name())
+	qualifier := types.RelativeTo(pkg)
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		fmt.Fprintf(w, "\t%s\n", types.ObjectString(obj, qualifier))
+		if named, ok := obj.Type().(*types.Named); ok && named.Obj() == obj {
+			for i := 0; i < named.NumMethods(); i++ {
+				fmt.Fprintf(w, "\t\t%s\n", types.ObjectString(named.Method(i), qualifier))
+			}
+		}
+	}
+}
+
+// writeLibraryPackage writes archive to pkgObj, and index, if not nil, to
+// the documentation index next to it.
+func (s *Session) writeLibraryPackage(archive *compiler.Archive, index *packageDocIndex, pkgObj string) error {
 	if err := os.MkdirAll(filepath.Dir(pkgObj), 0777); err != nil {
 		return err
 	}
+	if index != nil {
+		if err := writeDocIndex(index, docIndexPath(pkgObj)); err != nil {
+			return err
+		}
+	}
 
 	objFile, err := os.Create(pkgObj)
 	if err != nil {
 		return err
 	}
-	defer objFile.Close()
 
-	return compiler.WriteArchive(archive, objFile)
+	if err := compiler.WriteArchive(archive, objFile); err != nil {
+		objFile.Close()
+		os.Remove(pkgObj) // Don't leave a truncated archive behind to be picked up as up to date.
+		return err
+	}
+	if err := syncAndClose(objFile); err != nil {
+		os.Remove(pkgObj)
+		return err
+	}
+	return nil
 }
 
 func (s *Session) WriteCommandPackage(archive *compiler.Archive, pkgObj string) error {
+	switch s.options.Format {
+	case "":
+	case "cjs":
+		if s.options.Split {
+			return fmt.Errorf("the cjs output format can't be combined with split output")
+		}
+	default:
+		return fmt.Errorf("unknown output format %q, must be cjs", s.options.Format)
+	}
+	deps, err := compiler.ImportDependencies(archive, func(path string) (*compiler.Archive, error) {
+		if archive, ok := s.Archives[path]; ok {
+			return archive, nil
+		}
+		_, archive, err := s.buildImportPathWithSrcDir(path, "")
+		return archive, err
+	})
+	if err != nil {
+		return err
+	}
+	if s.options.EmitMetadata {
+		if err := os.MkdirAll(filepath.Dir(pkgObj), 0777); err != nil {
+			return err
+		}
+		if err := s.writeMetadata(deps, pkgObj+".json"); err != nil {
+			return err
+		}
+	}
+	if s.options.BundleReport != "" {
+		if err := s.writeBundleReport(deps, s.options.BundleReport); err != nil {
+			return err
+		}
+	}
+	if s.options.Split {
+		return s.writeCommandChunks(deps, pkgObj)
+	}
+	if s.options.Format == "cjs" {
+		return s.writeCommonJS(deps, pkgObj)
+	}
+
 	if err := os.MkdirAll(filepath.Dir(pkgObj), 0777); err != nil {
 		return err
 	}
@@ -695,33 +1406,193 @@ func (s *Session) WriteCommandPackage(archive *compiler.Archive, pkgObj string)
 	defer codeFile.Close()
 
 	sourceMapFilter := &compiler.SourceMapFilter{Writer: codeFile}
+	var m *sourcemap.Map
 	if s.options.CreateMapFile {
-		m := &sourcemap.Map{File: filepath.Base(pkgObj)}
+		m = &sourcemap.Map{File: filepath.Base(pkgObj)}
+		sourceMapFilter.MappingCallback = NewMappingCallback(m, s.options.GOROOT, s.options.GOPATH, s.options.MapToLocalDisk)
+	}
+
+	if err := s.WriteProgramCode(deps, sourceMapFilter); err != nil {
+		return err
+	}
+
+	if m != nil {
 		mapFile, err := os.Create(pkgObj + ".map")
 		if err != nil {
 			return err
 		}
-
-		defer func() {
-			m.WriteTo(mapFile)
+		if err := m.WriteTo(mapFile); err != nil {
 			mapFile.Close()
-			fmt.Fprintf(codeFile, "//# sourceMappingURL=%s.map\n", filepath.Base(pkgObj))
-		}()
+			return err
+		}
+		if err := syncAndClose(mapFile); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(codeFile, "//# sourceMappingURL=%s.map\n", filepath.Base(pkgObj)); err != nil {
+			return err
+		}
+	}
+	if err := syncAndClose(codeFile); err != nil {
+		return err
+	}
+	if s.options.Verify {
+		return verifyJS(pkgObj, archive.ImportPath)
+	}
+	return nil
+}
 
-		sourceMapFilter.MappingCallback = NewMappingCallback(m, s.options.GOROOT, s.options.GOPATH, s.options.MapToLocalDisk)
+// WriteProgramCode writes the program made of deps to w like compiler.WriteProgramCode,
+// preceded by the contents of options.PrependFile and followed by the contents
+// of options.AppendFile, if set. With options.Strict, the whole output including
+// the prepended and appended code is in strict mode. For the "worker" target,
+// the worker bootstrap is written after the program.
+func (s *Session) WriteProgramCode(deps []*compiler.Archive, w *compiler.SourceMapFilter) error {
+	return s.writeProgramChunks(deps, func(name string) (*compiler.SourceMapFilter, error) { return w, nil })
+}
+
+// writeProgramChunks is like WriteProgramCode, but writes the program in
+// chunks like compiler.WriteProgramChunks. The prepended code is part of the
+// prelude chunk, and the appended code is part of the last chunk. With
+// options.BuildID, the prelude chunk starts with a comment holding the build
+// ID of the program, see buildID.
+func (s *Session) writeProgramChunks(deps []*compiler.Archive, chunk func(name string) (*compiler.SourceMapFilter, error)) error {
+	var id string
+	if s.options.BuildID {
+		var err error
+		if id, err = s.buildID(deps); err != nil {
+			return err
+		}
 	}
+	return s.writeProgramChunksWithID(deps, chunk, id)
+}
 
-	deps, err := compiler.ImportDependencies(archive, func(path string) (*compiler.Archive, error) {
-		if archive, ok := s.Archives[path]; ok {
-			return archive, nil
+// buildID returns the build ID of the program made of deps, which is the
+// SHA-256 hash of the compiler version and the program without its build ID.
+// The program only depends on the sources of its packages and the options
+// affecting the output, so rebuilding it from the same sources with the same
+// options reproduces the ID.
+func (s *Session) buildID(deps []*compiler.Archive) (string, error) {
+	h := sha256.New()
+	if _, err := io.WriteString(h, compiler.Version+"\n"); err != nil {
+		return "", err
+	}
+	err := s.writeProgramChunksWithID(deps, func(string) (*compiler.SourceMapFilter, error) {
+		return &compiler.SourceMapFilter{Writer: h}, nil
+	}, "")
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeProgramChunksWithID is writeProgramChunks with the build ID id, or
+// none if it is empty.
+func (s *Session) writeProgramChunksWithID(deps []*compiler.Archive, chunk func(name string) (*compiler.SourceMapFilter, error), id string) error {
+	switch s.options.Target {
+	case "", "worker":
+	default:
+		return fmt.Errorf("unknown target %q", s.options.Target)
+	}
+	switch s.options.StackTrace {
+	case "", "full", "go", "none":
+	default:
+		return fmt.Errorf("unknown stack trace mode %q, must be full, go or none", s.options.StackTrace)
+	}
+	var w *compiler.SourceMapFilter
+	afterPrelude := false
+	err := compiler.WriteProgramChunks(deps, func(name string) (*compiler.SourceMapFilter, error) {
+		var err error
+		if w, err = chunk(name); err != nil {
+			return nil, err
 		}
-		_, archive, err := s.buildImportPathWithSrcDir(path, "")
-		return archive, err
+		if name != compiler.PreludeChunk {
+			if !afterPrelude && s.options.StackTrace != "" {
+				// The first chunk after the prelude is still inside of the
+				// function scope of the program, and runs before any package.
+				if _, err := fmt.Fprintf(w, "$stackTrace = %q;\n", s.options.StackTrace); err != nil {
+					return nil, err
+				}
+			}
+			afterPrelude = true
+			return w, nil
+		}
+		if id != "" {
+			if _, err := fmt.Fprintf(w, "// GopherJS build ID: %s\n", id); err != nil {
+				return nil, err
+			}
+		}
+		if s.options.Strict {
+			if _, err := w.Write([]byte("\"use strict\";\n")); err != nil {
+				return nil, err
+			}
+		}
+		if s.options.PrependFile != "" {
+			if err := writeFileContents(w, s.options.PrependFile); err != nil {
+				return nil, err
+			}
+		}
+		return w, nil
 	})
 	if err != nil {
 		return err
 	}
-	return compiler.WriteProgramCode(deps, sourceMapFilter)
+	if s.options.Target == "worker" {
+		if _, err := w.Write([]byte(workerBootstrap)); err != nil {
+			return err
+		}
+	}
+	if s.options.AppendFile != "" {
+		if err := writeFileContents(w, s.options.AppendFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workerBootstrap dispatches messages of the form {id, name, args} received by a
+// Web Worker to the function exported by the program under name, and posts back
+// {id, result} or {id, error}.
+const workerBootstrap = `(function(scope) {
+  scope.onmessage = function(e) {
+    var msg = e.data;
+    var fn = scope[msg.name];
+    if (typeof fn !== "function") {
+      scope.postMessage({ id: msg.id, error: "no exported function " + msg.name });
+      return;
+    }
+    var result;
+    try {
+      result = fn.apply(undefined, msg.args || []);
+    } catch (err) {
+      scope.postMessage({ id: msg.id, error: String(err) });
+      return;
+    }
+    scope.postMessage({ id: msg.id, result: result });
+  };
+})(self);
+`
+
+// writeFileContents copies the named file to w, terminated by a newline.
+func writeFileContents(w io.Writer, name string) error {
+	code, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if len(code) != 0 && code[len(code)-1] != '\n' {
+		code = append(code, '\n')
+	}
+	_, err = w.Write(code)
+	return err
+}
+
+// syncAndClose commits the contents of f to stable storage and closes it,
+// so that errors such as a full disk are reported rather than lost.
+func syncAndClose(f *os.File) error {
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
 
 func NewMappingCallback(m *sourcemap.Map, goroot, gopath string, localMap bool) func(generatedLine, generatedColumn int, originalPos token.Position) {
@@ -748,8 +1619,8 @@ func NewMappingCallback(m *sourcemap.Map, goroot, gopath string, localMap bool)
 	}
 }
 
-func jsFilesFromDir(dir string) ([]string, error) {
-	files, err := ioutil.ReadDir(dir)
+func jsFilesFromDir(bctx *build.Context, dir string) ([]string, error) {
+	files, err := readDir(bctx, dir)
 	if err != nil {
 		return nil, err
 	}
@@ -762,6 +1633,91 @@ func jsFilesFromDir(dir string) ([]string, error) {
 	return jsFiles, nil
 }
 
+// openFile opens the named file using the OpenFile hook of bctx, or the local file system if it is not set.
+func openFile(bctx *build.Context, name string) (io.ReadCloser, error) {
+	if bctx.OpenFile != nil {
+		return bctx.OpenFile(name)
+	}
+	return os.Open(name)
+}
+
+// readFile is like ioutil.ReadFile, but reads through openFile.
+func readFile(bctx *build.Context, name string) ([]byte, error) {
+	r, err := openFile(bctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// readDir lists dir using the ReadDir hook of bctx, or the local file system if it is not set.
+func readDir(bctx *build.Context, dir string) ([]os.FileInfo, error) {
+	if bctx.ReadDir != nil {
+		return bctx.ReadDir(dir)
+	}
+	return ioutil.ReadDir(dir)
+}
+
+// statFile returns the FileInfo of the file name in dir. The build.Context
+// hooks have no equivalent of os.Stat, so the ReadDir hook is consulted if set.
+func statFile(bctx *build.Context, dir, name string) (os.FileInfo, error) {
+	if bctx.ReadDir == nil {
+		return os.Stat(filepath.Join(dir, name))
+	}
+	infos, err := bctx.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		if info.Name() == name {
+			return info, nil
+		}
+	}
+	return nil, &os.PathError{Op: "stat", Path: filepath.Join(dir, name), Err: os.ErrNotExist}
+}
+
+// glob is like filepath.Glob, but lists directories using the ReadDir hook of
+// bctx. Like filepath.Glob, it ignores I/O errors.
+func glob(bctx *build.Context, pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	dir, file := filepath.Split(pattern)
+	switch dir {
+	case "":
+		dir = "."
+	case string(filepath.Separator):
+	default:
+		dir = dir[:len(dir)-1] // the trailing separator
+	}
+	dirs := []string{dir}
+	if strings.ContainsAny(dir, "*?[") {
+		var err error
+		if dirs, err = glob(bctx, dir); err != nil {
+			return nil, err
+		}
+	}
+	var matches []string
+	for _, dir := range dirs {
+		infos, err := readDir(bctx, dir)
+		if err != nil {
+			continue
+		}
+		var names []string
+		for _, info := range infos {
+			if matched, _ := filepath.Match(file, info.Name()); matched {
+				names = append(names, info.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			matches = append(matches, filepath.Join(dir, name))
+		}
+	}
+	return matches, nil
+}
+
 // hasGopathPrefix returns true and the length of the matched GOPATH workspace,
 // iff file has a prefix that matches one of the GOPATH workspaces.
 func hasGopathPrefix(file, gopath string) (hasGopathPrefix bool, prefixLen int) {
@@ -1,13 +1,36 @@
 package build
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/ast"
 	gobuild "go/build"
+	"go/parser"
+	"go/printer"
 	"go/token"
+	"go/types"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gopherjs/gopherjs/compiler"
+	"github.com/gopherjs/gopherjs/compiler/natives"
+	"github.com/gopherjs/gopherjs/compiler/prelude"
 	"github.com/kisielk/gotool"
 	"github.com/shurcooL/go/importgraphutil"
 )
@@ -77,7 +100,7 @@ func TestNativesDontImportExtraPackages(t *testing.T) {
 
 			// Use parseAndAugment to get a list of augmented AST files.
 			fset := token.NewFileSet()
-			files, err := parseAndAugment(bpkg, false, fset)
+			files, err := parseAndAugment(&gobuild.Default, bpkg, false, fset)
 			if err != nil {
 				t.Fatalf("github.com/gopherjs/gopherjs/build.parseAndAugment: %v", err)
 			}
@@ -116,7 +139,7 @@ func TestNativesDontImportExtraPackages(t *testing.T) {
 
 			// Use parseAndAugment to get a list of augmented AST files.
 			fset := token.NewFileSet()
-			files, err := parseAndAugment(bpkg, true, fset)
+			files, err := parseAndAugment(&gobuild.Default, bpkg, true, fset)
 			if err != nil {
 				t.Fatalf("github.com/gopherjs/gopherjs/build.parseAndAugment: %v", err)
 			}
@@ -158,7 +181,7 @@ func TestNativesDontImportExtraPackages(t *testing.T) {
 
 			// Use parseAndAugment to get a list of augmented AST files, then check only the external test files.
 			fset := token.NewFileSet()
-			files, err := parseAndAugment(bpkg, true, fset)
+			files, err := parseAndAugment(&gobuild.Default, bpkg, true, fset)
 			if err != nil {
 				t.Fatalf("github.com/gopherjs/gopherjs/build.parseAndAugment: %v", err)
 			}
@@ -187,6 +210,1577 @@ func TestNativesDontImportExtraPackages(t *testing.T) {
 	}
 }
 
+// TestNativesEmbedded checks that natives.FS, which is generated from the
+// compiler/natives/src folder for builds without the gopherjsdev tag, has the
+// current contents of every file there. Run go generate in compiler/natives
+// after changing the natives.
+func TestNativesEmbedded(t *testing.T) {
+	src := filepath.Join("..", "compiler", "natives", "src")
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Dir(src), path)
+		if err != nil {
+			return err
+		}
+		name := "/" + filepath.ToSlash(rel)
+		want, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f, err := natives.FS.Open(name)
+		if err != nil {
+			t.Errorf("%s is not embedded in natives.FS", name)
+			return nil
+		}
+		defer f.Close()
+		got, err := ioutil.ReadAll(f)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is stale in natives.FS", name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestImportVendor checks that imports are resolved from vendor directories
+// according to the Go vendoring rules, unless gobuild.IgnoreVendor is set.
+func TestImportVendor(t *testing.T) {
+	gopath, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(gopath string) { gobuild.Default.GOPATH = gopath }(gobuild.Default.GOPATH)
+	gobuild.Default.GOPATH = gopath
+	srcDir := filepath.Join(gopath, "src", "example.com", "app")
+
+	tests := []struct {
+		mode gobuild.ImportMode
+		want string
+	}{
+		{0, "example.com/app/vendor/example.com/lib"},
+		{gobuild.IgnoreVendor, "example.com/lib"},
+	}
+	for _, tt := range tests {
+		pkg, err := importWithSrcDir("example.com/lib", srcDir, tt.mode, "", nil)
+		if err != nil {
+			t.Fatalf("importWithSrcDir with mode %v: %v", tt.mode, err)
+		}
+		if pkg.ImportPath != tt.want {
+			t.Errorf("importWithSrcDir with mode %v: got import path %q, want %q", tt.mode, pkg.ImportPath, tt.want)
+		}
+	}
+}
+
+// TestDryRun checks that a dry run visits the dependencies of a package
+// without compiling anything.
+func TestDryRun(t *testing.T) {
+	gopath, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(gopath string) { gobuild.Default.GOPATH = gopath }(gobuild.Default.GOPATH)
+	gobuild.Default.GOPATH = gopath
+
+	s := NewSession(&Options{DryRun: true})
+	pkg, err := s.Import("example.com/app", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := s.BuildPackage(pkg)
+	if err != nil {
+		t.Fatalf("BuildPackage: %v", err)
+	}
+	if archive != nil {
+		t.Error("dry run compiled example.com/app")
+	}
+	for _, path := range []string{"example.com/app", "example.com/app/vendor/example.com/lib"} {
+		if archive, ok := s.Archives[path]; !ok || archive != nil {
+			t.Errorf("%s: got archive %v, %t, want nil, true", path, archive, ok)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(gopath, "pkg")); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote package objects: %v", err)
+		os.RemoveAll(filepath.Join(gopath, "pkg"))
+	}
+}
+
+// TestBuildVirtualFileSystem checks that package sources are read through
+// the file system hooks of Options rather than from the local file system.
+func TestBuildVirtualFileSystem(t *testing.T) {
+	files := map[string]string{
+		"/virtual/app/main.go": "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n",
+	}
+	options := &Options{
+		OpenFile: func(path string) (io.ReadCloser, error) {
+			content, ok := files[path]
+			if !ok {
+				return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+			}
+			return ioutil.NopCloser(strings.NewReader(content)), nil
+		},
+		ReadDir: func(dir string) ([]os.FileInfo, error) {
+			var infos []os.FileInfo
+			for path, content := range files {
+				if filepath.Dir(path) == dir {
+					infos = append(infos, virtualFileInfo{name: filepath.Base(path), size: int64(len(content))})
+				}
+			}
+			return infos, nil
+		},
+		IsDir: func(path string) bool {
+			return path == "/virtual/app"
+		},
+	}
+	s := NewSession(options)
+
+	bctx := s.buildContext()
+	pkg, err := bctx.ImportDir("/virtual/app", 0)
+	if err != nil {
+		t.Fatalf("ImportDir: %v", err)
+	}
+	if jsFiles, err := jsFilesFromDir(bctx, pkg.Dir); err != nil || len(jsFiles) != 0 {
+		t.Errorf("jsFilesFromDir: got %v, %v, want no files", jsFiles, err)
+	}
+	if len(pkg.GoFiles) != 1 || pkg.GoFiles[0] != "main.go" {
+		t.Fatalf("got GoFiles %v, want [main.go]", pkg.GoFiles)
+	}
+	if _, err := statFile(bctx, pkg.Dir, "main.go"); err != nil {
+		t.Errorf("statFile: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parseAndAugment(bctx, pkg, false, fset)
+	if err != nil {
+		t.Fatalf("parseAndAugment: %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Name.Name != "main" {
+		t.Errorf("got %d parsed files, want package main from the virtual file system", len(parsed))
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overlay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	app := filepath.Join(dir, "app")
+	files := map[string]string{
+		filepath.Join(app, "main.go"):        "package main\n\nfunc main() {\n\tprintln(\"original\")\n}\n",
+		filepath.Join(app, "removed.go"):     "package main\n\nfunc removed() {}\n",
+		filepath.Join(dir, "replacement.go"): "package main\n\nfunc main() {\n\tprintln(added())\n}\n",
+		filepath.Join(dir, "added.go"):       "package main\n\nfunc added() string { return \"replaced\" }\n",
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	replace, err := json.Marshal(map[string]map[string]string{"Replace": {
+		filepath.Join(app, "main.go"):    filepath.Join(dir, "replacement.go"),
+		filepath.Join(app, "removed.go"): "",
+		filepath.Join(app, "added.go"):   filepath.Join(dir, "added.go"),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlayFile := filepath.Join(dir, "overlay.json")
+	if err := ioutil.WriteFile(overlayFile, replace, 0644); err != nil {
+		t.Fatal(err)
+	}
+	overlay, err := ReadOverlay(overlayFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relApp, err := filepath.Rel(wd, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSession(&Options{Overlay: overlay})
+	if pkg, err := s.Import(relApp, 0); err != nil {
+		t.Errorf("Session.Import: %v", err)
+	} else if want := []string{"added.go", "main.go"}; !reflect.DeepEqual(pkg.GoFiles, want) {
+		t.Errorf("Session.Import: got GoFiles %v, want %v", pkg.GoFiles, want)
+	}
+	bctx := s.buildContext()
+	pkg, err := bctx.ImportDir(app, 0)
+	if err != nil {
+		t.Fatalf("ImportDir: %v", err)
+	}
+	if want := []string{"added.go", "main.go"}; !reflect.DeepEqual(pkg.GoFiles, want) {
+		t.Fatalf("got GoFiles %v, want %v", pkg.GoFiles, want)
+	}
+	fset := token.NewFileSet()
+	parsed, err := parseAndAugment(bctx, pkg, false, fset)
+	if err != nil {
+		t.Fatalf("parseAndAugment: %v", err)
+	}
+	archive, err := compiler.Compile("main", parsed, fset, &compiler.ImportContext{
+		Packages: make(map[string]*types.Package),
+		Import: func(path string) (*compiler.Archive, error) {
+			return nil, fmt.Errorf("unexpected import of %s", path)
+		},
+	}, false, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var code []byte
+	for _, d := range archive.Declarations {
+		code = append(code, d.DeclCode...)
+	}
+	if !bytes.Contains(code, []byte(`"replaced"`)) || bytes.Contains(code, []byte("original")) {
+		t.Errorf("replacement not compiled:\n%s", code)
+	}
+}
+
+func TestEmbedAssets(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", "package main\n\nvar assets string\n\nvar other = 1\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := &PackageData{Package: &gobuild.Package{Name: "main", ImportPath: "main", Dir: filepath.Join("testdata", "embed")}}
+
+	s := NewSession(&Options{Embed: []string{"*.txt=assets"}})
+	if err := s.embedAssets(s.buildContext(), pkg, []*ast.File{file}); err != nil {
+		t.Fatalf("embedAssets: %v", err)
+	}
+	lit, ok := findVarSpec([]*ast.File{file}, "assets").Values[0].(*ast.BasicLit)
+	if !ok {
+		t.Fatal("assets has no literal initializer")
+	}
+	if want := strconv.Quote(base64.StdEncoding.EncodeToString([]byte("hello, world"))); lit.Value != want {
+		t.Errorf("got %s, want %s", lit.Value, want)
+	}
+
+	// The assets are found and read through the file system hooks.
+	virtual := map[string]string{"/virtual/app/x.txt": "virtual"}
+	s = NewSession(&Options{
+		Embed: []string{"/virtual/*/*.txt=assets"},
+		OpenFile: func(path string) (io.ReadCloser, error) {
+			content, ok := virtual[path]
+			if !ok {
+				return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+			}
+			return ioutil.NopCloser(strings.NewReader(content)), nil
+		},
+		ReadDir: func(dir string) ([]os.FileInfo, error) {
+			switch dir {
+			case "/virtual":
+				return []os.FileInfo{virtualFileInfo{name: "app"}}, nil
+			case "/virtual/app":
+				return []os.FileInfo{virtualFileInfo{name: "x.txt", size: int64(len(virtual["/virtual/app/x.txt"]))}}, nil
+			}
+			return nil, &os.PathError{Op: "readdir", Path: dir, Err: os.ErrNotExist}
+		},
+	})
+	findVarSpec([]*ast.File{file}, "assets").Values = nil
+	if err := s.embedAssets(s.buildContext(), pkg, []*ast.File{file}); err != nil {
+		t.Fatalf("embedAssets with hooks: %v", err)
+	}
+	lit = findVarSpec([]*ast.File{file}, "assets").Values[0].(*ast.BasicLit)
+	if want := strconv.Quote(base64.StdEncoding.EncodeToString([]byte("virtual"))); lit.Value != want {
+		t.Errorf("with hooks: got %s, want %s", lit.Value, want)
+	}
+
+	for _, embed := range []string{"*.txt=other", "*.txt=missing", "*.none=assets", "assets"} {
+		s := NewSession(&Options{Embed: []string{embed}})
+		if err := s.embedAssets(s.buildContext(), pkg, []*ast.File{file}); err == nil {
+			t.Errorf("embedAssets with %q: got no error", embed)
+		}
+	}
+}
+
+// TestImporter checks that archives supplied by Options.Importer are used
+// in place of building packages from source, including their type information.
+func TestImporter(t *testing.T) {
+	compile := func(importPath, src string, importContext *compiler.ImportContext) *compiler.Archive {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, importPath+".go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive, err := compiler.Compile(importPath, []*ast.File{file}, fset, importContext, false, 0, false)
+		if err != nil {
+			t.Fatalf("compiling %s: %v", importPath, err)
+		}
+		return archive
+	}
+
+	lib := compile("example.com/supplied", "package supplied\n\nfunc Answer() int { return 42 }\n", &compiler.ImportContext{
+		Packages: make(map[string]*types.Package),
+		Import: func(path string) (*compiler.Archive, error) {
+			return nil, fmt.Errorf("unexpected import of %s", path)
+		},
+	})
+
+	var requested []string
+	s := NewSession(&Options{
+		Importer: func(path, srcDir string) (*compiler.Archive, error) {
+			requested = append(requested, path)
+			if path == lib.ImportPath {
+				return lib, nil
+			}
+			return nil, nil
+		},
+	})
+	compile("main", "package main\n\nimport \"example.com/supplied\"\n\nfunc main() { println(supplied.Answer()) }\n", &compiler.ImportContext{
+		Packages: s.Types,
+		Import:   s.BuildImportPath,
+	})
+	if len(requested) == 0 || requested[0] != lib.ImportPath {
+		t.Errorf("got imports %v, want %s", requested, lib.ImportPath)
+	}
+	if s.Archives[lib.ImportPath] != lib {
+		t.Error("supplied archive was not recorded in the session")
+	}
+	if s.Types[lib.ImportPath] == nil || s.Types[lib.ImportPath].Scope().Lookup("Answer") == nil {
+		t.Error("type information of the supplied archive was not loaded")
+	}
+}
+
+func TestMissingMain(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", "package main\n\nfunc helper() {}\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = compiler.Compile("main", []*ast.File{file}, fset, &compiler.ImportContext{
+		Packages: make(map[string]*types.Package),
+		Import: func(path string) (*compiler.Archive, error) {
+			return nil, fmt.Errorf("unexpected import of %s", path)
+		},
+	}, false, 0, false)
+	if want := "main.go:1:9: function main is undeclared in the main package"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("main.go", -1, 100)
+	var list compiler.ErrorList
+	for i := 0; i < 12; i++ {
+		list = append(list, types.Error{Fset: fset, Pos: file.Pos(i), Msg: fmt.Sprintf("error %d", i)})
+	}
+
+	got := (&Options{}).ErrorList(list)
+	if len(got) != 11 || got[9].Error() != "main.go:1:10: error 9" || got[10].Error() != "main.go:1:10: too many errors" {
+		t.Errorf("got %v, want the first 10 errors and too many errors", []error(got))
+	}
+	if got := (&Options{AllErrors: true}).ErrorList(list); len(got) != 12 {
+		t.Errorf("AllErrors: got %d errors, want 12", len(got))
+	}
+	err := errors.New("cannot find package")
+	if got := (&Options{}).ErrorList(err); len(got) != 1 || got[0] != err {
+		t.Errorf("got %v, want [%v]", []error(got), err)
+	}
+}
+
+// Range over integers is a Go 1.22 feature, which the go/types of Go 1.9
+// rejects before the code is translated.
+func TestRangeOverInt(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", "package main\n\nfunc main() {\n\tfor i := range 3 {\n\t\tprintln(i)\n\t}\n}\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = compiler.Compile("main", []*ast.File{file}, fset, &compiler.ImportContext{
+		Packages: make(map[string]*types.Package),
+		Import: func(path string) (*compiler.Archive, error) {
+			return nil, fmt.Errorf("unexpected import of %s", path)
+		},
+	}, false, 0, false)
+	if want := "main.go:4:17: cannot range over 3"; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
+
+func TestStdlibCache(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "errors.go", "package errors\n\nfunc New(text string) error { return nil }\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := compiler.Compile("errors", []*ast.File{file}, fset, &compiler.ImportContext{
+		Packages: make(map[string]*types.Package),
+		Import: func(path string) (*compiler.Archive, error) {
+			return nil, fmt.Errorf("unexpected import of %s", path)
+		},
+	}, false, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "stdlib-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, compiler.Version, "errors.a")
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compiler.WriteArchive(archive, f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s := NewSession(&Options{StdlibCache: dir})
+	pkg := &PackageData{Package: &gobuild.Package{Name: "errors", ImportPath: "errors", Goroot: true, Dir: filepath.Join(dir, "missing")}}
+	if got := s.stdlibCacheObj(&PackageData{Package: pkg.Package, IsTest: true}); got != "" {
+		t.Errorf("package built for tests taken from the cache %s", got)
+	}
+	if got := s.stdlibCacheObj(&PackageData{Package: &gobuild.Package{ImportPath: "errors"}}); got != "" {
+		t.Errorf("package outside of GOROOT taken from the cache %s", got)
+	}
+	loaded, err := s.BuildPackage(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded == nil || !pkg.UpToDate || s.Archives["errors"] != loaded {
+		t.Fatalf("errors was not loaded from the cache")
+	}
+	if s.Types["errors"] == nil || s.Types["errors"].Scope().Lookup("New") == nil {
+		t.Error("type information of the cached archive was not loaded")
+	}
+}
+
+func TestLargeFunctions(t *testing.T) {
+	src := `package large
+
+type T struct{}
+
+func (T) Small() {}
+
+func (*T) Big() int {
+	x := 0
+	x++
+	x++
+	x++
+	x++
+	return x
+}
+
+func Small() {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "large.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	importContext := &compiler.ImportContext{
+		Packages: make(map[string]*types.Package),
+		Import: func(path string) (*compiler.Archive, error) {
+			return nil, fmt.Errorf("unexpected import of %s", path)
+		},
+	}
+	archive, err := compiler.Compile("example.com/large", []*ast.File{file}, fset, importContext, false, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var smallSize int
+	for _, d := range archive.Declarations {
+		if (d.FullName == "example.com/large.Small" || d.FullName == "(example.com/large.T).Small") && len(d.DeclCode) > smallSize {
+			smallSize = len(d.DeclCode)
+		}
+	}
+	warnings := largeFunctions(importContext.Packages["example.com/large"], archive, []*ast.File{file}, fset, smallSize)
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0].Error(), "large.go:7:1: generated code for (*example.com/large.T).Big is ") {
+		t.Errorf("got warnings %q, want one for (*T).Big", warnings)
+	}
+}
+
+// TestSourceArchive checks that packages are found in and read from zip and
+// gzipped tar source archives.
+func TestSourceArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-srcarchive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"src/example.com/archived/archived.go":     "package archived\n\nconst Name = \"archived\"\n",
+		"src/example.com/archived/archived.inc.js": "console.log(\"archived\");\n",
+	}
+
+	zipName := filepath.Join(dir, "sources.zip")
+	f, err := os.Create(zipName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tarName := filepath.Join(dir, "sources.tar.gz")
+	f, err = os.Create(tarName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(tw, content)
+	}
+	tw.Close()
+	gw.Close()
+	f.Close()
+
+	for _, name := range []string{zipName, tarName} {
+		a, err := OpenSourceArchive(name)
+		if err != nil {
+			t.Fatalf("OpenSourceArchive(%s): %v", name, err)
+		}
+		s := NewSession(&Options{SourceArchive: a})
+		pkg, err := s.Import("example.com/archived", 0)
+		if err != nil {
+			t.Fatalf("%s: Import: %v", name, err)
+		}
+		if want := filepath.Join(a.Root, "src", "example.com", "archived"); pkg.Dir != want {
+			t.Errorf("%s: got Dir %s, want %s", name, pkg.Dir, want)
+		}
+		if len(pkg.GoFiles) != 1 || len(pkg.JSFiles) != 1 {
+			t.Errorf("%s: got GoFiles %v and JSFiles %v, want one of each", name, pkg.GoFiles, pkg.JSFiles)
+		}
+		parsed, err := parseAndAugment(s.buildContext(), pkg.Package, false, token.NewFileSet())
+		if err != nil {
+			t.Fatalf("%s: parseAndAugment: %v", name, err)
+		}
+		if len(parsed) != 1 || parsed[0].Name.Name != "archived" {
+			t.Errorf("%s: got %d parsed files, want package archived", name, len(parsed))
+		}
+	}
+
+	if _, err := OpenSourceArchive(filepath.Join(dir, "sources.rar")); err == nil {
+		t.Error("OpenSourceArchive of an unsupported format: got no error")
+	}
+}
+
+// TestImportOrder checks that the generated code doesn't depend on the order of
+// imports, even if imported packages have the same name.
+func TestImportOrder(t *testing.T) {
+	packages := make(map[string]*types.Package)
+	archives := make(map[string]*compiler.Archive)
+	compile := func(importPath, src string) *compiler.Archive {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "main.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive, err := compiler.Compile(importPath, []*ast.File{file}, fset, &compiler.ImportContext{
+			Packages: packages,
+			Import: func(path string) (*compiler.Archive, error) {
+				if archive, ok := archives[path]; ok {
+					return archive, nil
+				}
+				return nil, fmt.Errorf("unexpected import of %s", path)
+			},
+		}, false, 0, false)
+		if err != nil {
+			t.Fatalf("compiling %s: %v", importPath, err)
+		}
+		return archive
+	}
+	archives["example.com/a/rand"] = compile("example.com/a/rand", "package rand\n\nfunc A() {}\n")
+	archives["example.com/b/rand"] = compile("example.com/b/rand", "package rand\n\nfunc B() {}\n")
+
+	code := func(a *compiler.Archive) string {
+		var buf bytes.Buffer
+		for _, d := range a.Declarations {
+			buf.Write(d.DeclCode)
+			buf.Write(d.InitCode)
+		}
+		return buf.String()
+	}
+	ab := compile("main", "package main\n\nimport (\n\ta \"example.com/a/rand\"\n\tb \"example.com/b/rand\"\n)\n\nfunc main() {\n\ta.A()\n\tb.B()\n}\n")
+	ba := compile("main", "package main\n\nimport (\n\tb \"example.com/b/rand\"\n\ta \"example.com/a/rand\"\n)\n\nfunc main() {\n\ta.A()\n\tb.B()\n}\n")
+	if code(ab) != code(ba) {
+		t.Errorf("generated code depends on import order:\n%s\nversus:\n%s", code(ab), code(ba))
+	}
+}
+
+func TestInlining(t *testing.T) {
+	packages := make(map[string]*types.Package)
+	archives := make(map[string]*compiler.Archive)
+	compile := func(importPath, src string, optLevel int) *compiler.Archive {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "main.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive, err := compiler.Compile(importPath, []*ast.File{file}, fset, &compiler.ImportContext{
+			Packages: packages,
+			Import: func(path string) (*compiler.Archive, error) {
+				if archive, ok := archives[path]; ok {
+					return archive, nil
+				}
+				return nil, fmt.Errorf("unexpected import of %s", path)
+			},
+		}, false, optLevel, false)
+		if err != nil {
+			t.Fatalf("compiling %s: %v", importPath, err)
+		}
+		return archive
+	}
+	code := func(a *compiler.Archive) string {
+		var buf bytes.Buffer
+		for _, d := range a.Declarations {
+			buf.Write(d.DeclCode)
+		}
+		return buf.String()
+	}
+
+	lib := compile("example.com/geom", `package geom
+
+type Point struct{ x, y int }
+
+func (p *Point) X() int { return p.x }
+
+func Add(a, b int) int { return a + b }
+
+func Sub(a, b int) int { return b - a }
+
+func Print(a int) int { println(a); return a }
+
+func And(a, b bool) bool { return a && b }
+
+func Offset(p *Point, d int) int { return p.x + d }
+
+func Deref(p *int, d int) int { return *p + d }
+
+func Shift(d int, p *Point) int { return d + p.x }
+
+func Concat(a, b string) string { return a + b }
+`, 1)
+	archives["example.com/geom"] = lib
+	for _, name := range []string{"(*example.com/geom.Point).X", "example.com/geom.Add", "example.com/geom.Shift", "example.com/geom.Concat"} {
+		if _, ok := lib.Inlines[name]; !ok {
+			t.Errorf("%s is not inlinable", name)
+		}
+	}
+	for _, name := range []string{"example.com/geom.Sub", "example.com/geom.Print"} {
+		if _, ok := lib.Inlines[name]; ok {
+			t.Errorf("%s is inlinable, although it uses its parameters out of order or has side effects", name)
+		}
+	}
+	for _, name := range []string{"example.com/geom.And", "example.com/geom.Offset", "example.com/geom.Deref"} {
+		if _, ok := lib.Inlines[name]; ok {
+			t.Errorf("%s is inlinable, although it may skip an argument or dereference a pointer before the others", name)
+		}
+	}
+
+	src := `package main
+
+import "example.com/geom"
+
+func main() {
+	p := &geom.Point{}
+	println(p.X(), geom.Add(1, 2), geom.Sub(3, 4), geom.Print(5), geom.Concat("$inline1$", "b"))
+}
+`
+	main := code(compile("main", src, 1))
+	if !strings.Contains(main, `"$inline1$"`) || !strings.Contains(main, `"b"`) {
+		t.Errorf("arguments of inlined call Concat(\"$inline1$\", \"b\") are not substituted literally:\n%s", main)
+	}
+	for _, call := range []string{"X()", "Add(1, 2)", "Concat("} {
+		if strings.Contains(main, call) {
+			t.Errorf("call %s is not inlined:\n%s", call, main)
+		}
+	}
+	for _, call := range []string{"Sub(3, 4)", "Print(5)"} {
+		if !strings.Contains(main, call) {
+			t.Errorf("call %s is missing:\n%s", call, main)
+		}
+	}
+
+	delete(packages, "main")
+	if main := code(compile("main", src, 0)); !strings.Contains(main, "Add(1, 2)") {
+		t.Errorf("call Add(1, 2) is inlined without optimization:\n%s", main)
+	}
+}
+
+func TestIntrinsics(t *testing.T) {
+	src := `package math
+
+func Sqrt(x float64) float64
+
+func Floor(x float64) float64 { return floor(x) }
+
+func floor(x float64) float64 { return x }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "math.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := compiler.Compile("math", []*ast.File{file}, fset, &compiler.ImportContext{Packages: make(map[string]*types.Package)}, false, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	for _, d := range archive.Declarations {
+		buf.Write(d.DeclCode)
+	}
+	code := buf.String()
+	for _, name := range []string{"math.Sqrt", "math.Floor"} {
+		if !strings.Contains(code, prelude.Intrinsics[name]) {
+			t.Errorf("intrinsic of %s is missing:\n%s", name, code)
+		}
+	}
+	if strings.Contains(code, "native function not implemented") || strings.Contains(code, "return floor(x)") {
+		t.Errorf("function body used instead of intrinsic:\n%s", code)
+	}
+}
+
+func TestPackageMetadata(t *testing.T) {
+	src := `package api
+
+const Version = "1.0"
+
+var Count int
+
+type Point struct {
+	X    int
+	Y    int ` + "`json:\"y\"`" + `
+	hidden bool
+}
+
+func (p Point) Add(q Point) Point { return Point{X: p.X + q.X, Y: p.Y + q.Y} }
+
+func (p Point) norm() int { return p.X*p.X + p.Y*p.Y }
+
+func Origin() Point { return Point{} }
+
+func helper() {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "api.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := (&types.Config{}).Check("example.com/api", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(newPackageMetadata(pkg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"importPath":"example.com/api","name":"api",` +
+		`"consts":[{"name":"Version","type":"untyped string","value":"\"1.0\""}],` +
+		`"vars":[{"name":"Count","type":"int"}],` +
+		`"funcs":[{"name":"Origin","signature":"func() Point"}],` +
+		`"types":[{"name":"Point","underlying":"struct{X int; Y int \"json:\\\"y\\\"\"; hidden bool}",` +
+		`"fields":[{"name":"X","type":"int"},{"name":"Y","type":"int","tag":"json:\"y\""}],` +
+		`"methods":[{"name":"Add","signature":"func(q Point) Point"}]}]}`
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPreludeFeatures(t *testing.T) {
+	program := func(code string) string {
+		pkgs := []*compiler.Archive{{
+			ImportPath:   "main",
+			Declarations: []*compiler.Decl{{DeclCode: []byte(code)}},
+		}}
+		var buf bytes.Buffer
+		if err := compiler.WriteProgramCode(pkgs, &compiler.SourceMapFilter{Writer: &buf}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	tests := []struct {
+		code       string
+		divComplex bool
+		channels   bool
+		scheduler  bool
+		jsmapping  bool
+	}{
+		{"\tvar x = 1;\n", false, false, false, false},
+		{"\tvar x = $divComplex(a, b);\n", true, false, false, false},
+		{"\t$go(f, []);\n", false, false, true, false},
+		{"\tvar x = $recv(c);\n", false, true, true, false},
+		{"\t$close(c); $divComplex(a, b);\n", true, true, true, false},
+		{"\tvar x = $internalize(v, $String);\n", false, false, false, true},
+	}
+	for _, tt := range tests {
+		got := program(tt.code)
+		if has := strings.Contains(got, "var $divComplex = "); has != tt.divComplex {
+			t.Errorf("%q: complex division included = %v, want %v", tt.code, has, tt.divComplex)
+		}
+		if has := strings.Contains(got, "var $select = "); has != tt.channels {
+			t.Errorf("%q: channel operations included = %v, want %v", tt.code, has, tt.channels)
+		}
+		if has := strings.Contains(got, "var $schedule = "); has != tt.scheduler {
+			t.Errorf("%q: scheduler included = %v, want %v", tt.code, has, tt.scheduler)
+		}
+		if has := strings.Contains(got, "$go($mainPkg.$init, []);"); has != tt.scheduler {
+			t.Errorf("%q: main run by the scheduler = %v, want %v", tt.code, has, tt.scheduler)
+		}
+		if has := strings.Contains(got, "var $externalize = "); has != tt.jsmapping {
+			t.Errorf("%q: conversion of JavaScript values included = %v, want %v", tt.code, has, tt.jsmapping)
+		}
+	}
+
+	// A program that uses none of the features gets a prelude that is
+	// substantially smaller than the complete one.
+	if got, full := len(program("\tvar x = 1;\n")), len(prelude.Prelude); got > full*3/4 {
+		t.Errorf("trivial program is %d bytes, want at most 3/4 of the %d bytes of the complete prelude", got, full)
+	}
+}
+
+func TestWriteCommandChunks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	program := func(mainCode string) []*compiler.Archive {
+		return []*compiler.Archive{
+			{ImportPath: "runtime", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar rt = 1;\n")}}},
+			{ImportPath: "main", Declarations: []*compiler.Decl{{DeclCode: []byte(mainCode)}}},
+		}
+	}
+	s := NewSession(&Options{})
+	readChunks := func() []string {
+		manifest, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var chunks []string
+		if err := json.Unmarshal(manifest, &chunks); err != nil {
+			t.Fatal(err)
+		}
+		return chunks
+	}
+
+	deps := program("\tvar x = 1;\n")
+	if err := s.writeCommandChunks(deps, dir); err != nil {
+		t.Fatal(err)
+	}
+	chunks := readChunks()
+	if len(chunks) != 4 || !strings.HasPrefix(chunks[0], "prelude.") || !strings.HasPrefix(chunks[1], "pkg/runtime.") || !strings.HasPrefix(chunks[2], "pkg/main.") || !strings.HasPrefix(chunks[3], "start.") {
+		t.Fatalf("got chunks %v", chunks)
+	}
+	var joined bytes.Buffer
+	for _, chunk := range chunks {
+		code, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(chunk)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		joined.Write(code)
+	}
+	var whole bytes.Buffer
+	if err := s.WriteProgramCode(deps, &compiler.SourceMapFilter{Writer: &whole}); err != nil {
+		t.Fatal(err)
+	}
+	if joined.String() != whole.String() {
+		t.Error("concatenated chunks differ from the program")
+	}
+	if loader, err := ioutil.ReadFile(filepath.Join(dir, "loader.js")); err != nil || !strings.Contains(string(loader), strconv.Quote(chunks[2])) {
+		t.Errorf("loader.js doesn't list %s: %v\n%s", chunks[2], err, loader)
+	}
+
+	// Only the chunk of the changed package is replaced.
+	if err := s.writeCommandChunks(program("\tvar x = 2;\n"), dir); err != nil {
+		t.Fatal(err)
+	}
+	newChunks := readChunks()
+	for i := range chunks {
+		if changed := newChunks[i] != chunks[i]; changed != (i == 2) {
+			t.Errorf("chunk %d: %s became %s", i, chunks[i], newChunks[i])
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(chunks[2]))); !os.IsNotExist(err) {
+		t.Errorf("stale chunk %s was not removed: %v", chunks[2], err)
+	}
+}
+
+func TestRaceInstrumentation(t *testing.T) {
+	src := `package race
+
+import "sync"
+
+var counter int
+
+var mu sync.Mutex
+
+func inc() {
+	counter++
+}
+
+func send(ch chan int) {
+	local := 1
+	ch <- counter + local
+}
+
+func lock() {
+	mu.Lock()
+}
+`
+	compile := func(race bool) string {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "race.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sync := types.NewPackage("sync", "sync")
+		mutex := types.NewNamed(types.NewTypeName(token.NoPos, sync, "Mutex", nil), types.NewStruct(nil, nil), nil)
+		mutex.AddMethod(types.NewFunc(token.NoPos, sync, "Lock", types.NewSignature(types.NewVar(token.NoPos, sync, "m", types.NewPointer(mutex)), nil, nil, false)))
+		sync.Scope().Insert(mutex.Obj())
+		sync.MarkComplete()
+		archive, err := compiler.Compile("example.com/race", []*ast.File{file}, fset, &compiler.ImportContext{
+			Packages: map[string]*types.Package{"sync": sync},
+			Import: func(path string) (*compiler.Archive, error) {
+				return &compiler.Archive{ImportPath: path, Declarations: []*compiler.Decl{{FullName: "(*sync.Mutex).Lock"}}}, nil
+			},
+		}, false, 0, race)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var code []byte
+		for _, d := range archive.Declarations {
+			code = append(code, d.DeclCode...)
+		}
+		return string(code)
+	}
+
+	code := compile(true)
+	for _, want := range []string{`$raceWrite("example.com/race.counter"); counter = `, `$raceRead("example.com/race.counter")`, "$raceSync();"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("%s missing:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, `race.local`) {
+		t.Errorf("local variable instrumented:\n%s", code)
+	}
+	if n := strings.Count(code, "$raceSync();"); n != 2 {
+		t.Errorf("got %d synchronizing statements, want 2:\n%s", n, code)
+	}
+	if code := compile(false); strings.Contains(code, "$race") {
+		t.Errorf("instrumented without race:\n%s", code)
+	}
+}
+
+func TestWriteCommonJS(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node not found")
+	}
+	dir, err := ioutil.TempDir("", "gopherjs-cjs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The packages refer to and assign variables of the prelude, like the
+	// runtime does for $throwRuntimeError and main for $mainFinished.
+	deps := []*compiler.Archive{
+		{ImportPath: "runtime", Declarations: []*compiler.Decl{{InitCode: []byte("\t\t$throwRuntimeError = function(msg) { throw new Error(msg); };\n")}}},
+		{ImportPath: "example.com/lib", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar keys = function(m) { return $keys(m).join(); };\n\t$pkg.keys = keys;\n\t$pkg.name = \"$mainFinished\";\n")}}},
+		{ImportPath: "main", Imports: []string{"example.com/lib"}, Declarations: []*compiler.Decl{{InitCode: []byte("\t\t$module.exports.keys = $packages[\"example.com/lib\"].keys({a: 1, b: 2});\n\t\t$module.exports.throws = typeof $throwRuntimeError;\n\t\t$mainFinished = true;\n")}}},
+	}
+	s := NewSession(&Options{Format: "cjs"})
+	out := filepath.Join(dir, "out")
+	if err := s.writeCommonJS(deps, out); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"index.js", "prelude.js", "pkg/runtime.js", "pkg/example.com/lib.js", "pkg/main.js"} {
+		code, err := ioutil.ReadFile(filepath.Join(out, filepath.FromSlash(file)))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if bytes.Contains(code, []byte("eval(")) {
+			t.Errorf("%s uses eval", file)
+		}
+	}
+	main, err := ioutil.ReadFile(filepath.Join(out, "pkg", "main.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(main, []byte("require(\"./example.com/lib.js\");\n")) {
+		t.Errorf("pkg/main.js doesn't require its import:\n%s", main)
+	}
+	got, err := exec.Command("node", "-e", "var m = require(process.argv[1]); console.log(m.keys, m.throws);", out).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "a,b function\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A package module can be required without the rest of the program.
+	got, err = exec.Command("node", "-e", "var lib = require(process.argv[1]); console.log(lib.keys({c: 1}), lib.name);", filepath.Join(out, "pkg", "example.com", "lib.js")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "c $mainFinished\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if err := NewSession(&Options{Format: "esm"}).WriteCommandPackage(deps[2], out); err == nil || !strings.Contains(err.Error(), "unknown output format") {
+		t.Errorf("unknown format: got error %v", err)
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// TestLocalImports checks that packages imported by the same relative path from
+// different directories outside of the GOPATH workspaces are distinct.
+func TestLocalImports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a", "b"} {
+		if err := os.MkdirAll(filepath.Join(dir, name, "util"), 0777); err != nil {
+			t.Fatal(err)
+		}
+		src := "package util\n\nconst Name = \"" + name + "\"\n"
+		if err := ioutil.WriteFile(filepath.Join(dir, name, "util", "util.go"), []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewSession(&Options{})
+	pkgA, archiveA, err := s.buildImportPathWithSrcDir("./util", filepath.Join(dir, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgB, archiveB, err := s.buildImportPathWithSrcDir("./util", filepath.Join(dir, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkgA.ImportPath == pkgB.ImportPath || archiveA == archiveB {
+		t.Fatalf("./util of a and b resolved to the same package %s", pkgA.ImportPath)
+	}
+	if want := localImportPath(filepath.Join(dir, "b", "util")); pkgB.ImportPath != want {
+		t.Errorf("got import path %s, want %s", pkgB.ImportPath, want)
+	}
+	for pkg, want := range map[*PackageData]string{pkgA: `"a"`, pkgB: `"b"`} {
+		if name := s.Types[pkg.ImportPath].Scope().Lookup("Name"); name == nil || name.(*types.Const).Val().String() != want {
+			t.Errorf("%s: got constant %v, want Name = %s", pkg.ImportPath, name, want)
+		}
+	}
+}
+
+func TestTraceImports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, src := range map[string]string{
+		"top": "package top\n\nimport (\n\t\"../a\"\n\t\"../b\"\n)\n\nconst N = a.N + b.N\n",
+		"a":   "package a\n\nimport \"../c\"\n\nconst N = c.N\n",
+		"b":   "package b\n\nimport \"../c\"\n\nconst N = c.N\n",
+		"c":   "package c\n\nconst N = 1\n",
+	} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name, name+".go"), []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	s := NewSession(&Options{TraceImports: true})
+	_, _, err = s.buildImportPathWithSrcDir("./top", dir)
+	os.Stderr = stderr
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := func(name string) string { return localImportPath(filepath.Join(dir, name)) }
+	want := path("top") + "\n  " + path("a") + "\n    " + path("c") + "\n  " + path("b") + "\n    " + path("c") + " (see above)\n"
+	if string(out) != want {
+		t.Errorf("got trace:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestCheckLang(t *testing.T) {
+	for _, lang := range []string{"", "go1.0", "go1.8", "go1.9"} {
+		if err := CheckLang(lang); err != nil {
+			t.Errorf("CheckLang(%q): %v", lang, err)
+		}
+	}
+	for _, lang := range []string{"go1.10", "go1.22", "1.9", "go2.0", "go1.x", "go1.-1"} {
+		if err := CheckLang(lang); err == nil {
+			t.Errorf("CheckLang(%q): got no error", lang)
+		}
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "alias.go", "package p\n\ntype T = int\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lang := range []string{"", "go1.9"} {
+		if err := checkLangFeatures(lang, []*ast.File{file}, fset); err != nil {
+			t.Errorf("checkLangFeatures(%q): %v", lang, err)
+		}
+	}
+	want := "alias.go:3:8: type aliases require go1.9 or later (-lang was set to go1.8)"
+	if err := checkLangFeatures("go1.8", []*ast.File{file}, fset); err == nil || err.Error() != want {
+		t.Errorf("checkLangFeatures(\"go1.8\"): got %v, want %s", err, want)
+	}
+}
+
+func TestDocIndex(t *testing.T) {
+	const src = `// Package shapes computes areas.
+package shapes
+
+// Pi is close enough.
+const Pi = 3.14
+
+// Circle is round.
+type Circle struct{ R float64 }
+
+// NewCircle returns a circle of radius r.
+func NewCircle(r float64) Circle { return Circle{r} }
+
+// Area returns the area of c.
+func (c Circle) Area() float64 { return Pi * c.R * c.R }
+
+func (c Circle) scale() {}
+
+// Sum adds up the areas of circles.
+func Sum(circles ...Circle) float64 { return 0 }
+
+func helper() {}
+`
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "shapes.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := newDocIndex("example.com/shapes", []*ast.File{file}, fileSet)
+	if index.Name != "shapes" || index.Doc != "Package shapes computes areas.\n" {
+		t.Errorf("got package %s with doc %q", index.Name, index.Doc)
+	}
+	want := []docSymbol{
+		{Name: "Pi", Kind: "const", Doc: "Pi is close enough.\n"},
+		{Name: "Sum", Kind: "func", Doc: "Sum adds up the areas of circles.\n"},
+		{Name: "Circle", Kind: "type", Doc: "Circle is round.\n"},
+		{Name: "NewCircle", Kind: "func", Doc: "NewCircle returns a circle of radius r.\n"},
+		{Name: "Circle.Area", Kind: "method", Doc: "Area returns the area of c.\n"},
+	}
+	if !reflect.DeepEqual(index.Symbols, want) {
+		t.Errorf("got symbols %+v, want %+v", index.Symbols, want)
+	}
+	if got := docIndexPath(filepath.Join("pkg", "shapes.a")); got != filepath.Join("pkg", "shapes.index.json") {
+		t.Errorf("got index path %s", got)
+	}
+}
+
+func TestInstrumentCover(t *testing.T) {
+	const src = `package shapes
+
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func Apply(xs []int, f func(int) int) {
+	for i := range xs {
+		xs[i] = f(xs[i])
+	}
+	g := func() {}
+	g()
+}
+
+func Empty(x bool) {
+	if x {
+	}
+	switch {
+	case x:
+	}
+}
+`
+	fileSet := token.NewFileSet()
+	dir := filepath.Join("src", "shapes")
+	file, err := parser.ParseFile(fileSet, filepath.Join(dir, "shapes.go"), src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFile, err := parser.ParseFile(fileSet, filepath.Join(dir, "shapes_test.go"), "package shapes\n\nfunc helper() { Abs(1) }\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := &PackageData{Package: &gobuild.Package{
+		ImportPath: "example.com/shapes",
+		Dir:        dir,
+		GoFiles:    []string{"shapes.go", "shapes_test.go"},
+	}}
+	coverFiles, err := instrumentCover(pkg, []*ast.File{file, testFile}, fileSet, "count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverFiles) != 1 || coverFiles[0].Name != "example.com/shapes/shapes.go" || coverFiles[0].Var != "GoCover_0" {
+		t.Fatalf("got instrumented files %+v", coverFiles)
+	}
+	want := []CoverBlock{
+		{4, 2, 4, 11, 1},    // if x < 0 {
+		{7, 2, 7, 10, 1},    // return x
+		{5, 3, 5, 12, 1},    // return -x
+		{11, 2, 11, 20, 1},  // for i := range xs {
+		{14, 2, 14, 7, 1},   // g := up to the function literal
+		{15, 2, 15, 5, 1},   // g()
+		{12, 3, 12, 19, 1},  // xs[i] = f(xs[i])
+		{14, 14, 14, 16, 0}, // {} of the function literal
+		{19, 2, 19, 7, 1},   // if x {
+		{21, 2, 21, 9, 1},   // switch {
+		{19, 7, 20, 3, 0},   // empty body of if x
+		{22, 9, 22, 9, 0},   // empty case x
+	}
+	if !reflect.DeepEqual(coverFiles[0].Blocks, want) {
+		t.Errorf("got blocks %v, want %v", coverFiles[0].Blocks, want)
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fileSet, file); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "GoCover_0.Count[2]++\n\t\treturn -x") {
+		t.Errorf("counter of block 2 missing:\n%s", buf.String())
+	}
+
+	conf := types.Config{}
+	if _, err := conf.Check("example.com/shapes", fileSet, []*ast.File{file, testFile}, nil); err != nil {
+		t.Errorf("instrumented package does not type check: %v", err)
+	}
+	if _, err := instrumentCover(pkg, nil, fileSet, "atomic"); err == nil {
+		t.Error("got no error for cover mode atomic")
+	}
+}
+
+func TestNodeExecutable(t *testing.T) {
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	defer os.Setenv("GOPHERJS_NODE", os.Getenv("GOPHERJS_NODE"))
+
+	os.Setenv("GOPHERJS_NODE", filepath.Join("no", "such", "node"))
+	if _, err := NodeExecutable(); err == nil || !strings.Contains(err.Error(), "GOPHERJS_NODE") {
+		t.Errorf("missing $GOPHERJS_NODE: got error %v", err)
+	}
+	os.Setenv("GOPHERJS_NODE", "")
+	os.Setenv("PATH", "")
+	if _, err := NodeExecutable(); err == nil || !strings.Contains(err.Error(), "https://nodejs.org/") {
+		t.Errorf("node not in PATH: got error %v", err)
+	}
+
+	for version, want := range map[string]int{"v8.9.4\n": 8, "v10.0.0": 10, "4.2": 4} {
+		if got, err := parseNodeVersion(version); err != nil || got != want {
+			t.Errorf("parseNodeVersion(%q) = %d, %v, want %d", version, got, err, want)
+		}
+	}
+	if _, err := parseNodeVersion("unknown"); err == nil {
+		t.Error("parseNodeVersion(\"unknown\"): got no error")
+	}
+}
+
+func TestBuildEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-entry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := "package app\n\nvar Started bool\n\nfunc Start() { Started = true }\n\nfunc Add(a, b int) int { return a + b }\n\nfunc stop() {}\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "app.go"), []byte(src), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSession(&Options{})
+	pkg, _, err := s.buildImportPathWithSrcDir(".", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for entry, want := range map[string]string{
+		"Add":     "must take no arguments",
+		"stop":    "no exported function stop",
+		"Started": "no exported function Started",
+	} {
+		if err := s.BuildEntry(pkg, entry, filepath.Join(dir, "app.js")); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("entry %s: got error %v, want %q", entry, err, want)
+		}
+	}
+	pkg.Name = "main"
+	if err := s.BuildEntry(pkg, "Start", filepath.Join(dir, "app.js")); err == nil {
+		t.Error("command: got no error")
+	}
+}
+
+func TestLibraryMain(t *testing.T) {
+	pkg := types.NewPackage("example.com/lib", "lib")
+	sig := types.NewSignature(nil, nil, nil, false)
+	for _, name := range []string{"Sum", "helper", "Area"} {
+		pkg.Scope().Insert(types.NewFunc(token.NoPos, pkg, name, sig))
+	}
+	pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, "Count", types.Typ[types.Int]))
+
+	src := string(libraryMain(pkg, "lib"))
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+		t.Fatalf("%v:\n%s", err, src)
+	}
+	for _, want := range []string{`js.Global.Set("lib", api)`, `api.Set("Area", lib.Area)`, `api.Set("Sum", lib.Sum)`} {
+		if !strings.Contains(src, want) {
+			t.Errorf("%s missing:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "helper") || strings.Contains(src, "Count") {
+		t.Errorf("unexported function or variable exposed:\n%s", src)
+	}
+}
+
+func TestStubCgoFile(t *testing.T) {
+	src := `package cgo
+
+// #include <unistd.h>
+import "C"
+
+import "strings"
+
+type Handle C.int
+
+func Upper(s string) string {
+	return strings.ToUpper(C.GoString(C.CString(s)))
+}
+
+func (h Handle) Close() error {
+	C.close(C.int(h))
+	return nil
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "cgo.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !importsC(file) {
+		t.Fatal("importsC: got false, want true")
+	}
+	stub, err := stubCgoFile(fset, file, []byte(src), "example.com/cgo", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := &types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		pkg := types.NewPackage(path, path)
+		pkg.MarkComplete()
+		return pkg, nil
+	})}
+	if _, err := conf.Check("example.com/cgo", fset, []*ast.File{stub}, nil); err != nil {
+		t.Fatalf("stubbed file doesn't type check: %v", err)
+	}
+	for _, d := range stub.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		name := fn.Name.Name
+		if fn.Recv != nil {
+			name = "Handle." + name
+		}
+		lit := fn.Body.List[0].(*ast.ExprStmt).X.(*ast.CallExpr).Args[0].(*ast.BasicLit)
+		if want := strconv.Quote("example.com/cgo." + name + " is not supported by GopherJS, it is implemented using cgo"); lit.Value != want {
+			t.Errorf("%s: got panic %s, want %s", name, lit.Value, want)
+		}
+	}
+}
+
+func TestBundleReport(t *testing.T) {
+	deps := []*compiler.Archive{
+		{ImportPath: "runtime", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar rt = 1;\n")}}},
+		{ImportPath: "example.com/<heavy>", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar heavy = \"" + strings.Repeat("x", 100000) + "\";\n")}}},
+		{ImportPath: "main", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar x = 1;\n")}}},
+	}
+	s := NewSession(&Options{})
+	entries, err := s.bundleEntries(deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 || entries[0].Name != "example.com/<heavy>" || entries[1].Name != "(prelude)" {
+		t.Fatalf("got entries %v, want the heavy package and the prelude first", entries)
+	}
+	var whole bytes.Buffer
+	if err := s.WriteProgramCode(deps, &compiler.SourceMapFilter{Writer: &whole}); err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, e := range entries {
+		total += e.Size
+	}
+	if total != whole.Len() {
+		t.Errorf("got total size %d, want size of program %d", total, whole.Len())
+	}
+
+	f, err := ioutil.TempFile("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if err := s.writeBundleReport(deps, f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	report, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "<td>example.com/&lt;heavy&gt;</td>") || strings.Contains(string(report), "<heavy>") {
+		t.Errorf("package missing from report or not escaped:\n%s", report)
+	}
+}
+
+func TestStackTraceMode(t *testing.T) {
+	deps := []*compiler.Archive{
+		{ImportPath: "runtime", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar rt = 1;\n")}}},
+		{ImportPath: "main", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar x = 1;\n")}}},
+	}
+	code := func(mode string) (string, error) {
+		var buf bytes.Buffer
+		err := NewSession(&Options{StackTrace: mode}).WriteProgramCode(deps, &compiler.SourceMapFilter{Writer: &buf})
+		return buf.String(), err
+	}
+	program, err := code("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, j := strings.Index(program, "\n$stackTrace = \"go\";\n"), strings.Index(program, `$packages["runtime"] = (`); i == -1 || j == -1 || i > j {
+		t.Errorf("stack trace mode not set before the packages:\n%s", program)
+	}
+	if program, err := code(""); err != nil || strings.Contains(program, "\n$stackTrace = ") {
+		t.Errorf("default stack trace mode: got error %v or mode set", err)
+	}
+	if _, err := code("verbose"); err == nil {
+		t.Error("unknown stack trace mode: got no error")
+	}
+}
+
+func TestBuildID(t *testing.T) {
+	deps := func(code string) []*compiler.Archive {
+		return []*compiler.Archive{
+			{ImportPath: "runtime", Declarations: []*compiler.Decl{{DeclCode: []byte("\tvar rt = 1;\n")}}},
+			{ImportPath: "main", Declarations: []*compiler.Decl{{DeclCode: []byte(code)}}},
+		}
+	}
+	program := func(options *Options, deps []*compiler.Archive) string {
+		var buf bytes.Buffer
+		if err := NewSession(options).WriteProgramCode(deps, &compiler.SourceMapFilter{Writer: &buf}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	plain := program(&Options{}, deps("\tvar x = 1;\n"))
+	if strings.Contains(plain, "build ID") {
+		t.Errorf("got a build ID without options.BuildID")
+	}
+	withID := program(&Options{BuildID: true}, deps("\tvar x = 1;\n"))
+	sum := sha256.Sum256([]byte(compiler.Version + "\n" + plain))
+	if want := "// GopherJS build ID: " + hex.EncodeToString(sum[:]) + "\n" + plain; withID != want {
+		t.Errorf("got program starting with %q, want the hash of the program without ID", strings.SplitN(withID, "\n", 2)[0])
+	}
+	if again := program(&Options{BuildID: true}, deps("\tvar x = 1;\n")); again != withID {
+		t.Error("rebuilding gave a different build ID")
+	}
+	if other := program(&Options{BuildID: true}, deps("\tvar x = 2;\n")); strings.SplitN(other, "\n", 2)[0] == strings.SplitN(withID, "\n", 2)[0] {
+		t.Error("different programs have the same build ID")
+	}
+}
+
+func TestVerifyJS(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node not found")
+	}
+	f, err := ioutil.TempFile("", "verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString("var a = 1;\nvar b = 2;\n")
+	if err := verifyJS(f.Name(), "main"); err != nil {
+		t.Errorf("valid code: %v", err)
+	}
+
+	f.WriteString("var c = ;\nvar d = 4;\n")
+	f.Close()
+	err = verifyJS(f.Name(), "main")
+	if err == nil {
+		t.Fatal("invalid code: got no error")
+	}
+	for _, want := range []string{"GopherJS bug", "SyntaxError", "      2  var b = 2;\n>     3  var c = ;\n      4  var d = 4;"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error doesn't contain %q:\n%v", want, err)
+		}
+	}
+}
+
+type virtualFileInfo struct {
+	name string
+	size int64
+}
+
+func (fi virtualFileInfo) Name() string       { return fi.name }
+func (fi virtualFileInfo) Size() int64        { return fi.size }
+func (fi virtualFileInfo) Mode() os.FileMode  { return 0444 }
+func (fi virtualFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi virtualFileInfo) IsDir() bool        { return false }
+func (fi virtualFileInfo) Sys() interface{}   { return nil }
+
 // stringSet is used to print a set of strings in a more readable way.
 type stringSet map[string]struct{}
 
@@ -197,3 +1791,84 @@ func (m stringSet) String() string {
 	}
 	return fmt.Sprintf("%q", s)
 }
+
+func TestStampVCS(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir, err := ioutil.TempDir("", "buildvcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	parse := func(src string) []*ast.File {
+		file, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []*ast.File{file}
+	}
+	stamped := func() string {
+		files := parse("package main\n\nvar vcsRevision string\n")
+		if err := stampVCS(&PackageData{Package: &gobuild.Package{Name: "main", ImportPath: "main", Dir: dir}}, files); err != nil {
+			t.Fatalf("stampVCS: %v", err)
+		}
+		value, err := strconv.Unquote(findVarSpec(files, "vcsRevision").Values[0].(*ast.BasicLit).Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	if got := stamped(); got != "" {
+		t.Errorf("outside of a repository: got %q, want \"\"", got)
+	}
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=gopher", "-c", "user.email=gopher@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "initial")
+	if got, want := stamped(), git("rev-parse", "HEAD"); got != want {
+		t.Errorf("got revision %q, want %q", got, want)
+	}
+
+	pkg := &PackageData{Package: &gobuild.Package{Name: "main", ImportPath: "main", Dir: dir}}
+	for _, src := range []string{"package main\n\nvar vcsRevision = \"x\"\n", "package main\n\nvar vcsRevision, other string\n"} {
+		if err := stampVCS(pkg, parse(src)); err == nil {
+			t.Errorf("stampVCS of %q: got no error", src)
+		}
+	}
+	files := parse("package main\n\nvar other string\n")
+	if err := stampVCS(pkg, files); err != nil {
+		t.Errorf("stampVCS of a main without %s: %v", vcsRevisionVar, err)
+	}
+	if spec := findVarSpec(files, "other"); len(spec.Values) != 0 {
+		t.Errorf("stampVCS of a main without %s changed other", vcsRevisionVar)
+	}
+
+	// Test and synthesized main packages are not stamped, so a value that
+	// stampVCS rejects is left alone.
+	src := "package main\n\nvar vcsRevision = \"x\"\n\nfunc main() {}\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0666); err != nil {
+		t.Fatal(err)
+	}
+	for _, pkg := range []*PackageData{
+		{Package: &gobuild.Package{Name: "main", ImportPath: "main", Dir: dir, GoFiles: []string{"main.go"}}, IsTest: true},
+		{Package: &gobuild.Package{Name: "main", ImportPath: "main", Dir: dir, GoFiles: []string{"main.go"}}, Synthetic: true},
+	} {
+		if _, err := NewSession(&Options{BuildVCS: true}).BuildPackage(pkg); err != nil {
+			t.Errorf("IsTest %v, Synthetic %v: %v", pkg.IsTest, pkg.Synthetic, err)
+		}
+	}
+	pkg = &PackageData{Package: &gobuild.Package{Name: "main", ImportPath: "main", Dir: dir, GoFiles: []string{"main.go"}}}
+	if _, err := NewSession(&Options{BuildVCS: true}).BuildPackage(pkg); err == nil {
+		t.Error("user main: got no error for a vcsRevision with a value")
+	}
+}
@@ -0,0 +1,372 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/compiler"
+)
+
+// cjsProgramStart is the code opening the function of the program in the
+// prelude chunk, see compiler.WriteProgramChunks. The prelude follows it.
+const cjsProgramStart = "(function() {\n\"use strict\";\n\n"
+
+// writeCommonJS writes the program made of deps into the directory dir as
+// CommonJS modules. prelude.js holds the prelude and exports its variables.
+// pkg/<import path>.js holds the code of a package: it requires prelude.js and
+// the modules of the packages it imports, and exports the package object, so
+// that it can also be required on its own, which doesn't initialize the
+// package. index.js requires the modules in the order of deps and starts the
+// program. Source maps are not written.
+//
+// The code of the packages refers to the variables of the prelude by name. A
+// module declares local variables for those it uses, except for the variables
+// that are assigned after the prelude is loaded, like $curGoroutine or
+// $mainFinished: prelude.js exports them as accessor properties, and the
+// references to them are rewritten into accesses of these properties.
+func (s *Session) writeCommonJS(deps []*compiler.Archive, dir string) error {
+	type chunk struct {
+		name string
+		code *bytes.Buffer
+	}
+	var chunks []chunk
+	err := s.writeProgramChunks(deps, func(name string) (*compiler.SourceMapFilter, error) {
+		buf := new(bytes.Buffer)
+		chunks = append(chunks, chunk{name, buf})
+		return &compiler.SourceMapFilter{Writer: buf}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	prelude := chunks[0].code.Bytes()
+	start := bytes.LastIndex(prelude, []byte(cjsProgramStart))
+	if chunks[0].name != compiler.PreludeChunk || start == -1 {
+		return fmt.Errorf("the prelude of the program is not where the cjs output format expects it")
+	}
+	start += len(cjsProgramStart)
+	vars := make(map[string]bool)
+	for _, name := range jsTopLevelVars(prelude[start:]) {
+		vars[name] = true
+	}
+	mutable := make(map[string]bool)
+	for _, c := range chunks {
+		for name := range jsAssignedNames(c.code.Bytes()) {
+			if vars[name] {
+				mutable[name] = true
+			}
+		}
+	}
+
+	var files []string
+	write := func(file string, code []byte) error {
+		filename := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+			return err
+		}
+		files = append(files, file)
+		return ioutil.WriteFile(filename, code, 0666)
+	}
+	// header starts the module file with the prelude module and local
+	// variables for the variables of the prelude used by code.
+	header := func(file string, code *[]byte) *bytes.Buffer {
+		var used []string
+		*code, used = rewritePreludeRefs(*code, vars, mutable)
+		b := new(bytes.Buffer)
+		fmt.Fprintf(b, "\"use strict\";\nvar $prelude = require(%s);\n", cjsRequirePath(file, "prelude.js"))
+		for i, name := range used {
+			sep := ", "
+			if i == 0 {
+				sep = "var "
+			}
+			fmt.Fprintf(b, "%s%s = $prelude.%s", sep, name, name)
+		}
+		if len(used) != 0 {
+			b.WriteString(";\n")
+		}
+		return b
+	}
+
+	var exports bytes.Buffer
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if mutable[name] {
+			fmt.Fprintf(&exports, "Object.defineProperty(exports, %q, { enumerable: true, get: function() { return %s; }, set: function(v) { %s = v; } });\n", name, name, name)
+			continue
+		}
+		fmt.Fprintf(&exports, "exports.%s = %s;\n", name, name)
+	}
+	if err := write("prelude.js", append(append(prelude[:len(prelude):len(prelude)], exports.Bytes()...), "}).call(this);\n"...)); err != nil {
+		return err
+	}
+
+	modules := make(map[string]string)
+	for _, c := range chunks[1 : len(chunks)-1] {
+		modules[c.name] = path.Join("pkg", c.name) + ".js"
+	}
+	var index []byte
+	for i, c := range chunks[1 : len(chunks)-1] {
+		file := modules[c.name]
+		code := c.code.Bytes()
+		b := header(file, &code)
+		for _, imp := range deps[i].Imports {
+			if dep, ok := modules[imp]; ok {
+				fmt.Fprintf(b, "require(%s);\n", cjsRequirePath(file, dep))
+			}
+		}
+		b.Write(code)
+		fmt.Fprintf(b, "module.exports = $prelude.$packages[%s];\n", strconv.Quote(c.name))
+		if err := write(file, b.Bytes()); err != nil {
+			return err
+		}
+		index = append(index, fmt.Sprintf("require(%s);\n", cjsRequirePath("index.js", file))...)
+	}
+	code := chunks[len(chunks)-1].code.Bytes()
+	b := header("index.js", &code)
+	b.WriteString("$prelude.$module = module;\n")
+	b.Write(index)
+	// The code that starts the program closes the function of the program.
+	b.WriteString("(function() {\n")
+	b.Write(code)
+	if err := write("index.js", b.Bytes()); err != nil {
+		return err
+	}
+
+	if s.options.Verify {
+		mainPkg := deps[len(deps)-1].ImportPath
+		for _, file := range files {
+			if err := verifyJS(filepath.Join(dir, filepath.FromSlash(file)), mainPkg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cjsRequirePath returns the quoted argument of require in the module file
+// from for the module file to, both relative to the output directory.
+func cjsRequirePath(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		panic(err) // both paths are relative to the same directory
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return strconv.Quote(rel)
+}
+
+// rewritePreludeRefs returns code with the references to the variables vars of
+// the prelude that are mutable replaced by accesses of the properties of
+// $prelude, and the sorted names of the other variables of vars it refers to.
+func rewritePreludeRefs(code []byte, vars, mutable map[string]bool) ([]byte, []string) {
+	var out []byte
+	used := make(map[string]bool)
+	tokens := jsTokens(code)
+	last := 0
+	for i, t := range tokens {
+		name := string(code[t.start:t.end])
+		if !t.word || !vars[name] || !jsIsReference(code, tokens, i) {
+			continue
+		}
+		if !mutable[name] {
+			used[name] = true
+			continue
+		}
+		out = append(append(append(out, code[last:t.start]...), "$prelude."...), name...)
+		last = t.end
+	}
+	out = append(out, code[last:]...)
+	var names []string
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return out, names
+}
+
+// jsToken is a token of JavaScript code at code[start:end]. Words are
+// identifiers, keywords and numbers. The other tokens are literals of
+// strings, templates and regular expressions, and single characters of
+// punctuation.
+type jsToken struct {
+	start, end int
+	word       bool
+}
+
+// jsRegexpKeywords are the keywords after which a slash starts a regular
+// expression rather than being a division.
+var jsRegexpKeywords = map[string]bool{"case": true, "delete": true, "do": true, "else": true, "in": true, "instanceof": true, "new": true, "return": true, "throw": true, "typeof": true, "void": true}
+
+// jsTokens returns the tokens of the JavaScript code, without white space and
+// comments.
+func jsTokens(code []byte) []jsToken {
+	var tokens []jsToken
+	regexpAllowed := func() bool {
+		if len(tokens) == 0 {
+			return true
+		}
+		last := tokens[len(tokens)-1]
+		if last.word {
+			return jsRegexpKeywords[string(code[last.start:last.end])]
+		}
+		return last.end-last.start == 1 && !strings.ContainsRune(")]}", rune(code[last.start]))
+	}
+	for i := 0; i < len(code); {
+		c := code[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && i+1 < len(code) && code[i+1] == '/':
+			if j := bytes.IndexByte(code[i:], '\n'); j != -1 {
+				i += j
+			} else {
+				i = len(code)
+			}
+		case c == '/' && i+1 < len(code) && code[i+1] == '*':
+			if j := bytes.Index(code[i+2:], []byte("*/")); j != -1 {
+				i += j + 4
+			} else {
+				i = len(code)
+			}
+		case c == '"' || c == '\'' || c == '`' || c == '/' && regexpAllowed():
+			j := jsLiteralEnd(code, i)
+			tokens = append(tokens, jsToken{start: i, end: j})
+			i = j
+		case isJSWordByte(c):
+			j := i + 1
+			for j < len(code) && isJSWordByte(code[j]) {
+				j++
+			}
+			tokens = append(tokens, jsToken{start: i, end: j, word: true})
+			i = j
+		default:
+			tokens = append(tokens, jsToken{start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens
+}
+
+// jsLiteralEnd returns the end of the literal of a string, template or
+// regular expression, including its flags, that starts at code[i].
+func jsLiteralEnd(code []byte, i int) int {
+	quote := code[i]
+	inClass := false
+	for j := i + 1; j < len(code); j++ {
+		switch c := code[j]; {
+		case c == '\\':
+			j++
+		case quote == '/' && c == '[':
+			inClass = true
+		case quote == '/' && c == ']':
+			inClass = false
+		case c == quote && !inClass:
+			j++
+			for quote == '/' && j < len(code) && isJSWordByte(code[j]) {
+				j++
+			}
+			return j
+		}
+	}
+	return len(code)
+}
+
+func isJSWordByte(c byte) bool {
+	return c == '$' || c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// isJSPunct reports whether the token t of code is the punctuation c.
+func isJSPunct(code []byte, t jsToken, c byte) bool {
+	return !t.word && t.end-t.start == 1 && code[t.start] == c
+}
+
+// jsIsReference reports whether the word tokens[i] of code refers to a
+// variable, rather than being a property name or the key of an object literal.
+func jsIsReference(code []byte, tokens []jsToken, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := tokens[i-1]
+	if isJSPunct(code, prev, '.') {
+		return false
+	}
+	isKey := (isJSPunct(code, prev, '{') || isJSPunct(code, prev, ',')) && i+1 < len(tokens) && isJSPunct(code, tokens[i+1], ':')
+	return !isKey
+}
+
+// jsTopLevelVars returns the names declared by the var statements at the top
+// level of the JavaScript code, which start with $.
+func jsTopLevelVars(code []byte) []string {
+	var names []string
+	depth := 0
+	declaring, expectName := false, false
+	for _, t := range jsTokens(code) {
+		text := string(code[t.start:t.end])
+		if !t.word {
+			switch text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			case ",":
+				expectName = declaring && depth == 0
+			case ";":
+				if depth == 0 {
+					declaring = false
+				}
+			}
+			continue
+		}
+		if depth == 0 && text == "var" {
+			declaring, expectName = true, true
+			continue
+		}
+		if expectName && strings.HasPrefix(text, "$") {
+			names = append(names, text)
+		}
+		expectName = false
+	}
+	return names
+}
+
+// jsAssignOps are the operators assigning to the variable before them.
+var jsAssignOps = []string{"++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<=", ">>=", ">>>=", "**="}
+
+// jsAssignedNames returns the names of the variables that the JavaScript code
+// assigns to, other than in their var declarations.
+func jsAssignedNames(code []byte) map[string]bool {
+	names := make(map[string]bool)
+	tokens := jsTokens(code)
+	for i, t := range tokens {
+		if !t.word || !jsIsReference(code, tokens, i) {
+			continue
+		}
+		if i > 0 && tokens[i-1].word && string(code[tokens[i-1].start:tokens[i-1].end]) == "var" {
+			continue
+		}
+		after := bytes.TrimLeft(code[t.end:], " \t\n\r")
+		before := bytes.TrimRight(code[:t.start], " \t\n\r")
+		assigned := len(after) != 0 && after[0] == '=' && (len(after) == 1 || after[1] != '=' && after[1] != '>') ||
+			bytes.HasSuffix(before, []byte("++")) || bytes.HasSuffix(before, []byte("--"))
+		for _, op := range jsAssignOps {
+			assigned = assigned || bytes.HasPrefix(after, []byte(op))
+		}
+		if assigned {
+			names[string(code[t.start:t.end])] = true
+		}
+	}
+	return names
+}
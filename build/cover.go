@@ -0,0 +1,217 @@
+package build
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CoverBlock is a block of statements of an instrumented file, with the
+// fields of testing.CoverBlock.
+type CoverBlock struct {
+	Line0, Col0, Line1, Col1, Stmts int
+}
+
+// CoverFile is a source file instrumented for coverage. Its package level
+// variable Var is a struct whose field Count is an array with a counter for
+// each block, which is set or incremented when the block is executed.
+type CoverFile struct {
+	Name   string // the name of the file in coverage profiles
+	Var    string
+	Blocks []CoverBlock
+}
+
+// instrumentCover inserts coverage counters in mode, "set" or "count", into
+// the files of pkg that are not test files, and returns them in the order of
+// files.
+func instrumentCover(pkg *PackageData, files []*ast.File, fileSet *token.FileSet, mode string) ([]CoverFile, error) {
+	if mode != "set" && mode != "count" {
+		return nil, fmt.Errorf("unknown cover mode %q, must be set or count", mode)
+	}
+	covered := make(map[string]bool)
+	for _, name := range pkg.GoFiles {
+		if !strings.HasSuffix(name, "_test.go") {
+			covered[filepath.Join(pkg.Dir, name)] = true
+		}
+	}
+
+	var coverFiles []CoverFile
+	for _, file := range files {
+		name := fileSet.Position(file.Pos()).Filename
+		if !covered[name] {
+			continue // test and native files
+		}
+		c := &coverer{
+			fileSet: fileSet,
+			mode:    mode,
+			file: CoverFile{
+				Name: path.Join(strings.TrimSuffix(pkg.ImportPath, "_test"), filepath.Base(name)),
+				Var:  "GoCover_" + strconv.Itoa(len(coverFiles)),
+			},
+		}
+		c.instrument(file)
+		coverFiles = append(coverFiles, c.file)
+	}
+	return coverFiles, nil
+}
+
+// coverer instruments a single file, in the way of cmd/cover: every list of
+// statements is split into basic blocks, which end with a statement that may
+// change the flow of control or contains a function literal, and a counter
+// is inserted at the start of each block.
+type coverer struct {
+	fileSet *token.FileSet
+	mode    string
+	file    CoverFile
+}
+
+func (c *coverer) instrument(file *ast.File) {
+	clauseLists := make(map[*ast.BlockStmt]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SwitchStmt:
+			clauseLists[n.Body] = true
+		case *ast.TypeSwitchStmt:
+			clauseLists[n.Body] = true
+		case *ast.SelectStmt:
+			clauseLists[n.Body] = true
+		case *ast.BlockStmt:
+			if !clauseLists[n] {
+				n.List = c.addCounters(n.List, n.Lbrace, n.Rbrace+1)
+			}
+		case *ast.CaseClause:
+			n.Body = c.addCounters(n.Body, n.Colon+1, n.Colon+1)
+		case *ast.CommClause:
+			n.Body = c.addCounters(n.Body, n.Colon+1, n.Colon+1)
+		}
+		return true
+	})
+
+	// var GoCover_N struct{ Count [blocks]uint32 }
+	file.Decls = append(file.Decls, &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{&ast.ValueSpec{
+			Names: []*ast.Ident{ast.NewIdent(c.file.Var)},
+			Type: &ast.StructType{Fields: &ast.FieldList{List: []*ast.Field{{
+				Names: []*ast.Ident{ast.NewIdent("Count")},
+				Type: &ast.ArrayType{
+					Len: &ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(len(c.file.Blocks))},
+					Elt: ast.NewIdent("uint32"),
+				},
+			}}}},
+		}},
+	})
+}
+
+// addCounters returns list with a counter inserted before each basic block.
+// Like cmd/cover, an empty list gets a counter of its own, for a block of no
+// statements from pos to end.
+func (c *coverer) addCounters(list []ast.Stmt, pos, end token.Pos) []ast.Stmt {
+	if len(list) == 0 {
+		return []ast.Stmt{c.counter(pos, end, 0)}
+	}
+	var result []ast.Stmt
+	for len(list) != 0 {
+		n := 1
+		for n < len(list) && !endsBlock(list[n-1]) {
+			if _, isLabeled := list[n].(*ast.LabeledStmt); isLabeled {
+				break // Labels can be jumped to, so they start a block.
+			}
+			n++
+		}
+		result = append(result, c.counter(list[0].Pos(), blockEnd(list[n-1]), n))
+		result = append(result, list[:n]...)
+		list = list[n:]
+	}
+	return result
+}
+
+// counter records the block from pos to end with stmts statements and
+// returns the statement incrementing its counter.
+func (c *coverer) counter(pos, end token.Pos, stmts int) ast.Stmt {
+	start, stop := c.fileSet.Position(pos), c.fileSet.Position(end)
+	c.file.Blocks = append(c.file.Blocks, CoverBlock{start.Line, start.Column, stop.Line, stop.Column, stmts})
+
+	count := &ast.IndexExpr{
+		X:     &ast.SelectorExpr{X: ast.NewIdent(c.file.Var), Sel: ast.NewIdent("Count")},
+		Index: &ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(len(c.file.Blocks) - 1)},
+	}
+	if c.mode == "count" {
+		return &ast.IncDecStmt{X: count, Tok: token.INC}
+	}
+	return &ast.AssignStmt{Lhs: []ast.Expr{count}, Tok: token.ASSIGN, Rhs: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "1"}}}
+}
+
+// endsBlock reports whether the statement s ends a basic block.
+func endsBlock(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.BlockStmt, *ast.BranchStmt, *ast.ForStmt, *ast.IfStmt, *ast.RangeStmt, *ast.ReturnStmt,
+		*ast.SelectStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt:
+		return true
+	case *ast.LabeledStmt:
+		return endsBlock(s.Stmt)
+	case *ast.ExprStmt:
+		if call, ok := s.X.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "panic" && len(call.Args) == 1 {
+				return true
+			}
+		}
+	}
+	return firstFuncLit(s) != nil
+}
+
+// blockEnd returns where the basic block ended by the statement s ends, which
+// is before the body of control statements and function literals, since they
+// have blocks of their own.
+func blockEnd(s ast.Stmt) token.Pos {
+	var body *ast.BlockStmt
+	var header []ast.Node
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		return s.Lbrace
+	case *ast.LabeledStmt:
+		return blockEnd(s.Stmt)
+	case *ast.IfStmt:
+		body, header = s.Body, []ast.Node{s.Init, s.Cond}
+	case *ast.ForStmt:
+		body, header = s.Body, []ast.Node{s.Init, s.Cond, s.Post}
+	case *ast.RangeStmt:
+		body, header = s.Body, []ast.Node{s.X}
+	case *ast.SwitchStmt:
+		body, header = s.Body, []ast.Node{s.Init, s.Tag}
+	case *ast.TypeSwitchStmt:
+		body, header = s.Body, []ast.Node{s.Init, s.Assign}
+	case *ast.SelectStmt:
+		body = s.Body
+	default:
+		if lit := firstFuncLit(s); lit != nil {
+			return lit.Pos()
+		}
+		return s.End()
+	}
+	for _, n := range header {
+		if lit := firstFuncLit(n); lit != nil {
+			return lit.Pos()
+		}
+	}
+	return body.Lbrace
+}
+
+// firstFuncLit returns the first function literal in n, or nil.
+func firstFuncLit(n ast.Node) *ast.FuncLit {
+	var lit *ast.FuncLit
+	if n == nil {
+		return nil // absent parts of statements
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		if l, ok := n.(*ast.FuncLit); ok && lit == nil {
+			lit = l
+		}
+		return lit == nil
+	})
+	return lit
+}
@@ -0,0 +1,86 @@
+package build
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// packageDocIndex lists the documented exported symbols of a package, as
+// written next to its archive by options.DocIndex.
+type packageDocIndex struct {
+	ImportPath string      `json:"importPath"`
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc,omitempty"`
+	Symbols    []docSymbol `json:"symbols"`
+}
+
+// docSymbol is an exported symbol with its doc comment. Kind is "const",
+// "var", "func", "type" or "method", and methods are named Type.Method.
+type docSymbol struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Doc  string `json:"doc,omitempty"`
+}
+
+// docIndexPath returns the name of the documentation index of the package
+// with the archive pkgObj.
+func docIndexPath(pkgObj string) string {
+	return strings.TrimSuffix(pkgObj, filepath.Ext(pkgObj)) + ".index.json"
+}
+
+// newDocIndex extracts the documentation of the package importPath from its
+// files. It filters the files of unexported declarations, so they must not be
+// used afterwards.
+func newDocIndex(importPath string, files []*ast.File, fileSet *token.FileSet) *packageDocIndex {
+	astPkg := &ast.Package{Files: make(map[string]*ast.File)}
+	for _, file := range files {
+		astPkg.Name = file.Name.Name
+		astPkg.Files[fileSet.Position(file.Pos()).Filename] = file
+	}
+	p := doc.New(astPkg, importPath, 0)
+
+	index := &packageDocIndex{ImportPath: importPath, Name: p.Name, Doc: p.Doc, Symbols: []docSymbol{}}
+	addValues := func(values []*doc.Value, kind string) {
+		for _, v := range values {
+			for _, name := range v.Names {
+				index.Symbols = append(index.Symbols, docSymbol{Name: name, Kind: kind, Doc: v.Doc})
+			}
+		}
+	}
+	addFuncs := func(funcs []*doc.Func, prefix, kind string) {
+		for _, f := range funcs {
+			index.Symbols = append(index.Symbols, docSymbol{Name: prefix + f.Name, Kind: kind, Doc: f.Doc})
+		}
+	}
+	addValues(p.Consts, "const")
+	addValues(p.Vars, "var")
+	addFuncs(p.Funcs, "", "func")
+	for _, t := range p.Types {
+		index.Symbols = append(index.Symbols, docSymbol{Name: t.Name, Kind: "type", Doc: t.Doc})
+		addValues(t.Consts, "const")
+		addValues(t.Vars, "var")
+		addFuncs(t.Funcs, "", "func")
+		addFuncs(t.Methods, t.Name+".", "method")
+	}
+	return index
+}
+
+// writeDocIndex writes index as JSON to the file name.
+func writeDocIndex(index *packageDocIndex, name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(index); err != nil {
+		f.Close()
+		return err
+	}
+	return syncAndClose(f)
+}
@@ -0,0 +1,109 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"go/build"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+// BuildLibrary builds the package pkg, which must not be a command, as a
+// program that exposes the exported functions of the package as the methods
+// of an object, and writes it to pkgObj. Under Node.js, the object is the
+// module.exports of the program, elsewhere it is the global variable name.
+// Arguments and results are converted between Go and JavaScript like for
+// functions passed to js.Object.Set. The object is populated once the package
+// is initialized, so under Node.js, initialization must not block for the
+// functions to be available to require.
+func (s *Session) BuildLibrary(pkg *PackageData, name, pkgObj string) error {
+	if pkg.IsCommand() {
+		return fmt.Errorf("cannot build command %s as a library", pkg.ImportPath)
+	}
+	archive, err := s.BuildPackage(pkg)
+	if err != nil {
+		return err
+	}
+	if s.options.DryRun {
+		return nil
+	}
+	return s.buildSyntheticMain(pkg, libraryMain(s.Types[archive.ImportPath], name), pkgObj)
+}
+
+// BuildEntry builds the package pkg, which must not be a command, as a
+// program whose entry point is the exported function entry of the package,
+// which is called without arguments once the packages are initialized, like
+// the main function of a command. The program is written to pkgObj.
+func (s *Session) BuildEntry(pkg *PackageData, entry, pkgObj string) error {
+	if pkg.IsCommand() {
+		return fmt.Errorf("cannot use %s as the entry point of command %s, which starts with main", entry, pkg.ImportPath)
+	}
+	archive, err := s.BuildPackage(pkg)
+	if err != nil {
+		return err
+	}
+	if s.options.DryRun {
+		return nil
+	}
+	fun, ok := s.Types[archive.ImportPath].Scope().Lookup(entry).(*types.Func)
+	if !ok || !fun.Exported() {
+		return fmt.Errorf("%s has no exported function %s to use as the entry point", pkg.ImportPath, entry)
+	}
+	if sig := fun.Type().(*types.Signature); sig.Params().Len() != 0 || sig.Results().Len() != 0 {
+		return fmt.Errorf("entry point %s.%s must take no arguments and return no results, like main", pkg.ImportPath, entry)
+	}
+	src := fmt.Sprintf("package main\n\nimport lib \".\"\n\nfunc main() {\n\tlib.%s()\n}\n", entry)
+	return s.buildSyntheticMain(pkg, []byte(src), pkgObj)
+}
+
+// buildSyntheticMain builds the source src of a main package importing pkg
+// and writes it as a command to pkgObj.
+func (s *Session) buildSyntheticMain(pkg *PackageData, src []byte, pkgObj string) error {
+	f, err := ioutil.TempFile("", "gopherjs-main")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(src); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// The package is imported as "." from its own directory, which resolves to
+	// it regardless of where it was found.
+	main := &PackageData{
+		Package: &build.Package{
+			Name:       "main",
+			ImportPath: "main",
+			Dir:        pkg.Dir,
+			GoFiles:    []string{f.Name()},
+		},
+		Synthetic: true,
+	}
+	mainArchive, err := s.BuildPackage(main)
+	if err != nil {
+		return err
+	}
+	return s.WriteCommandPackage(mainArchive, pkgObj)
+}
+
+// libraryMain returns the source of the main package of a library built from
+// the package pkg, which populates the API object with its exported functions.
+func libraryMain(pkg *types.Package, name string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("package main\n\nimport (\n\tlib \".\"\n\t\"github.com/gopherjs/gopherjs/js\"\n)\n\n")
+	buf.WriteString("func main() {\n\tvar api *js.Object\n\tif js.Module != js.Undefined {\n\t\tapi = js.Module.Get(\"exports\")\n\t} else {\n")
+	fmt.Fprintf(&buf, "\t\tapi = js.Global.Get(\"Object\").New()\n\t\tjs.Global.Set(%s, api)\n\t}\n", strconv.Quote(name))
+	for _, n := range pkg.Scope().Names() {
+		if fun, ok := pkg.Scope().Lookup(n).(*types.Func); ok && fun.Exported() {
+			fmt.Fprintf(&buf, "\tapi.Set(%s, lib.%s)\n", strconv.Quote(n), n)
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,106 @@
+package build
+
+import (
+	"encoding/json"
+	"go/types"
+	"os"
+
+	"github.com/gopherjs/gopherjs/compiler"
+)
+
+// packageMetadata describes the exported API of a package, as written by
+// options.EmitMetadata. Types are formatted relative to the package.
+type packageMetadata struct {
+	ImportPath string          `json:"importPath"`
+	Name       string          `json:"name"`
+	Consts     []valueMetadata `json:"consts,omitempty"`
+	Vars       []valueMetadata `json:"vars,omitempty"`
+	Funcs      []funcMetadata  `json:"funcs,omitempty"`
+	Types      []typeMetadata  `json:"types,omitempty"`
+}
+
+type valueMetadata struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+type funcMetadata struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+type typeMetadata struct {
+	Name       string          `json:"name"`
+	Underlying string          `json:"underlying"`
+	Fields     []fieldMetadata `json:"fields,omitempty"`
+	Methods    []funcMetadata  `json:"methods,omitempty"`
+}
+
+type fieldMetadata struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Embedded bool   `json:"embedded,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+}
+
+// newPackageMetadata collects the exported package level declarations of pkg.
+func newPackageMetadata(pkg *types.Package) *packageMetadata {
+	qualifier := types.RelativeTo(pkg)
+	md := &packageMetadata{ImportPath: pkg.Path(), Name: pkg.Name()}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Const:
+			md.Consts = append(md.Consts, valueMetadata{Name: name, Type: types.TypeString(obj.Type(), qualifier), Value: obj.Val().ExactString()})
+		case *types.Var:
+			md.Vars = append(md.Vars, valueMetadata{Name: name, Type: types.TypeString(obj.Type(), qualifier)})
+		case *types.Func:
+			md.Funcs = append(md.Funcs, funcMetadata{Name: name, Signature: types.TypeString(obj.Type(), qualifier)})
+		case *types.TypeName:
+			t := typeMetadata{Name: name, Underlying: types.TypeString(obj.Type().Underlying(), qualifier)}
+			if st, ok := obj.Type().Underlying().(*types.Struct); ok {
+				for i := 0; i < st.NumFields(); i++ {
+					if f := st.Field(i); f.Exported() {
+						t.Fields = append(t.Fields, fieldMetadata{Name: f.Name(), Type: types.TypeString(f.Type(), qualifier), Embedded: f.Anonymous(), Tag: st.Tag(i)})
+					}
+				}
+			}
+			if named, ok := obj.Type().(*types.Named); ok {
+				for i := 0; i < named.NumMethods(); i++ {
+					if m := named.Method(i); m.Exported() {
+						t.Methods = append(t.Methods, funcMetadata{Name: m.Name(), Signature: types.TypeString(m.Type(), qualifier)})
+					}
+				}
+			}
+			md.Types = append(md.Types, t)
+		}
+	}
+	return md
+}
+
+// writeMetadata writes the exported API of the packages of a program to the
+// JSON file name, in the order in which they appear in the program.
+func (s *Session) writeMetadata(deps []*compiler.Archive, name string) error {
+	var mds []*packageMetadata
+	for _, dep := range deps {
+		if pkg := s.Types[dep.ImportPath]; pkg != nil {
+			mds = append(mds, newPackageMetadata(pkg))
+		}
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(mds); err != nil {
+		f.Close()
+		return err
+	}
+	return syncAndClose(f)
+}
@@ -0,0 +1,54 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// MinNodeVersion is the oldest major version of Node.js that programs are
+// known to run with.
+const MinNodeVersion = 4
+
+// NodeExecutable returns the Node.js executable that programs are run and
+// verified with, which is $GOPHERJS_NODE if set, or node from the PATH. The
+// error for a missing executable explains how to install Node.js.
+func NodeExecutable() (string, error) {
+	if node := os.Getenv("GOPHERJS_NODE"); node != "" {
+		path, err := exec.LookPath(node)
+		if err != nil {
+			return "", fmt.Errorf("GOPHERJS_NODE is set to %s, which is not a Node.js executable: %v", node, err)
+		}
+		return path, nil
+	}
+	path, err := exec.LookPath("node")
+	if err != nil {
+		return "", fmt.Errorf("Node.js is required to run the generated code, but node was not found in the PATH. Install Node.js %d.x or newer from https://nodejs.org/, or set GOPHERJS_NODE to the node executable.", MinNodeVersion)
+	}
+	return path, nil
+}
+
+// NodeVersion returns the major version of the Node.js executable node.
+func NodeVersion(node string) (int, error) {
+	out, err := exec.Command(node, "--version").Output()
+	if err != nil {
+		return 0, err
+	}
+	return parseNodeVersion(string(out))
+}
+
+// parseNodeVersion returns the major version of the output of node --version,
+// like "v8.9.4".
+func parseNodeVersion(version string) (int, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		s = s[:i]
+	}
+	major, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected Node.js version %q", strings.TrimSpace(version))
+	}
+	return major, nil
+}
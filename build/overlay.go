@@ -0,0 +1,134 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ReadOverlay reads the JSON file name, in the format of the -overlay flag of
+// go build: an object whose Replace field maps the paths of files to the
+// paths of the files to read instead, or to "" for files to treat as absent.
+// Relative paths are relative to the current directory. The returned map, for
+// Options.Overlay, has absolute paths.
+func ReadOverlay(name string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var overlay struct {
+		Replace map[string]string
+	}
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return nil, fmt.Errorf("parsing overlay %s: %v", name, err)
+	}
+	replace := make(map[string]string, len(overlay.Replace))
+	for from, to := range overlay.Replace {
+		absFrom, err := filepath.Abs(from)
+		if err != nil {
+			return nil, err
+		}
+		if to != "" {
+			if to, err = filepath.Abs(to); err != nil {
+				return nil, err
+			}
+		}
+		replace[absFrom] = to
+	}
+	return replace, nil
+}
+
+// overlayFileInfo is the FileInfo of a replacement file, under the name of
+// the file it replaces.
+type overlayFileInfo struct {
+	os.FileInfo
+	name string
+}
+
+func (fi overlayFileInfo) Name() string { return fi.name }
+
+// mountOverlay makes the hooks of bctx read the files of overlay, which maps
+// absolute paths to the paths of their replacements in the local file system,
+// or to "" for absent files, instead of the files themselves. Replacements of
+// files that don't exist add them to their directories.
+func mountOverlay(bctx *build.Context, overlay map[string]string) {
+	outer := *bctx
+
+	bctx.OpenFile = func(name string) (io.ReadCloser, error) {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+		replacement, ok := overlay[abs]
+		if !ok {
+			return openFile(&outer, name)
+		}
+		if replacement == "" {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		return os.Open(replacement)
+	}
+	bctx.ReadDir = func(dir string) ([]os.FileInfo, error) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		infos, err := readDir(&outer, dir)
+		if err != nil && !hasOverlayFiles(overlay, abs) {
+			return nil, err
+		}
+		byName := make(map[string]os.FileInfo)
+		for _, info := range infos {
+			byName[info.Name()] = info
+		}
+		for name, replacement := range overlay {
+			if filepath.Dir(name) != abs {
+				continue
+			}
+			base := filepath.Base(name)
+			if replacement == "" {
+				delete(byName, base)
+				continue
+			}
+			info, err := os.Stat(replacement)
+			if err != nil {
+				return nil, err
+			}
+			byName[base] = overlayFileInfo{info, base}
+		}
+		merged := make([]os.FileInfo, 0, len(byName))
+		for _, info := range byName {
+			merged = append(merged, info)
+		}
+		sort.Slice(merged, func(i, j int) bool { return merged[i].Name() < merged[j].Name() })
+		return merged, nil
+	}
+	bctx.IsDir = func(name string) bool {
+		if abs, err := filepath.Abs(name); err == nil && hasOverlayFiles(overlay, abs) {
+			return true
+		}
+		if outer.IsDir != nil {
+			return outer.IsDir(name)
+		}
+		fi, err := os.Stat(name)
+		return err == nil && fi.IsDir()
+	}
+}
+
+// hasOverlayFiles reports whether overlay replaces files below the directory
+// dir, which makes it exist.
+func hasOverlayFiles(overlay map[string]string, dir string) bool {
+	prefix := dir + string(filepath.Separator)
+	for name, replacement := range overlay {
+		if replacement != "" && strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package build
+
+import (
+	"html/template"
+	"os"
+	"sort"
+
+	"github.com/gopherjs/gopherjs/compiler"
+)
+
+// bundleEntry is a part of a program in a bundle report, with the number of
+// bytes of JavaScript it contributes to the output.
+type bundleEntry struct {
+	Name string
+	Size int
+}
+
+// byteCounter is an io.Writer counting the bytes written to it.
+type byteCounter struct {
+	n int
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += len(p)
+	return len(p), nil
+}
+
+// bundleEntries returns the parts of the program made of deps, which are the
+// prelude, each package and the code that starts the program, sorted by
+// decreasing size. The sizes are those of the code written for the
+// program, after dead code elimination, without source maps.
+func (s *Session) bundleEntries(deps []*compiler.Archive) ([]bundleEntry, error) {
+	var entries []bundleEntry
+	var counter *byteCounter
+	flush := func() {
+		if counter != nil {
+			entries[len(entries)-1].Size = counter.n
+		}
+	}
+	err := s.writeProgramChunks(deps, func(chunk string) (*compiler.SourceMapFilter, error) {
+		flush()
+		name := chunk
+		switch chunk {
+		case compiler.PreludeChunk:
+			name = "(prelude)"
+		case compiler.StartChunk:
+			name = "(start)"
+		}
+		entries = append(entries, bundleEntry{Name: name})
+		counter = new(byteCounter)
+		return &compiler.SourceMapFilter{Writer: counter}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	flush()
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+	return entries, nil
+}
+
+// writeBundleReport writes an HTML page to the file name, showing how many
+// bytes each package contributes to the program made of deps, as a treemap
+// and a table.
+func (s *Session) writeBundleReport(deps []*compiler.Archive, name string) error {
+	entries, err := s.bundleEntries(deps)
+	if err != nil {
+		return err
+	}
+	total := 0
+	for _, e := range entries {
+		total += e.Size
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = bundleReport.Execute(f, struct {
+		Program string
+		Total   int
+		Entries []bundleEntry
+	}{deps[len(deps)-1].ImportPath, total, entries})
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return syncAndClose(f)
+}
+
+var bundleReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(n, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(n) * 100 / float64(total)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Bundle of {{.Program}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+#treemap { position: relative; width: 100%; height: 480px; }
+#treemap div { position: absolute; box-sizing: border-box; border: 1px solid #fff; overflow: hidden; font-size: 12px; padding: 2px; color: #fff; }
+table { border-collapse: collapse; margin-top: 2em; }
+td, th { padding: 2px 1em; text-align: left; }
+td.size { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Bundle of {{.Program}}</h1>
+<p>{{.Total}} bytes of JavaScript in {{len .Entries}} parts.</p>
+<div id="treemap"></div>
+<table>
+<tr><th>Part</th><th>Bytes</th><th>Share</th></tr>
+{{- range .Entries}}
+<tr><td>{{.Name}}</td><td class="size">{{.Size}}</td><td class="size">{{printf "%.1f%%" (percent .Size $.Total)}}</td></tr>
+{{- end}}
+</table>
+<script>
+(function() {
+  var entries = [{{range $i, $e := .Entries}}{{if $i}}, {{end}}{name: {{$e.Name}}, size: {{$e.Size}}}{{end}}];
+  var container = document.getElementById("treemap");
+  var total = 0;
+  entries.forEach(function(e) { total += e.size; });
+  if (total === 0) {
+    return;
+  }
+  var scale = container.clientWidth * container.clientHeight / total;
+
+  /* Squarified treemap: entries are laid out in rows along the shorter side
+     of the remaining rectangle, as long as that improves the aspect ratios. */
+  var worst = function(row, side) {
+    var sum = 0, max = 0, min = Infinity;
+    row.forEach(function(e) { var a = e.size * scale; sum += a; max = Math.max(max, a); min = Math.min(min, a); });
+    return Math.max(side * side * max / (sum * sum), sum * sum / (side * side * min));
+  };
+  var rect = { x: 0, y: 0, w: container.clientWidth, h: container.clientHeight };
+  var place = function(row) {
+    var sum = 0;
+    row.forEach(function(e) { sum += e.size * scale; });
+    var horizontal = rect.w >= rect.h;
+    var thickness = sum / (horizontal ? rect.h : rect.w);
+    var offset = 0;
+    row.forEach(function(e, i) {
+      var length = e.size * scale / thickness;
+      var div = document.createElement("div");
+      div.style.left = (horizontal ? rect.x : rect.x + offset) + "px";
+      div.style.top = (horizontal ? rect.y + offset : rect.y) + "px";
+      div.style.width = (horizontal ? thickness : length) + "px";
+      div.style.height = (horizontal ? length : thickness) + "px";
+      div.style.background = "hsl(" + (i * 47 + row.length * 101) % 360 + ", 45%, 45%)";
+      div.title = e.name + ": " + e.size + " bytes";
+      div.textContent = e.name;
+      container.appendChild(div);
+      offset += length;
+    });
+    if (horizontal) {
+      rect.x += thickness;
+      rect.w -= thickness;
+    } else {
+      rect.y += thickness;
+      rect.h -= thickness;
+    }
+  };
+  var row = [];
+  entries.forEach(function(e) {
+    if (e.size === 0) {
+      return;
+    }
+    var side = Math.min(rect.w, rect.h);
+    if (row.length !== 0 && worst(row.concat([e]), side) > worst(row, side)) {
+      place(row);
+      row = [];
+    }
+    row.push(e);
+  });
+  if (row.length !== 0) {
+    place(row);
+  }
+})();
+</script>
+</body>
+</html>
+`))
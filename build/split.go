@@ -0,0 +1,115 @@
+package build
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/compiler"
+)
+
+// writeCommandChunks writes the program made of deps into the directory dir,
+// as one file for the prelude, one for each package and one for the code that
+// starts the program. The files are named after a hash of their contents, so
+// that browsers can cache them across builds for as long as they don't change.
+// manifest.json lists them in load order, and loader.js loads and runs them.
+// Files listed by a previous manifest that are no longer needed are removed.
+func (s *Session) writeCommandChunks(deps []*compiler.Archive, dir string) error {
+	var oldChunks []string
+	if manifest, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json")); err == nil {
+		json.Unmarshal(manifest, &oldChunks)
+	}
+
+	var chunks []string
+	var name string
+	var buf *bytes.Buffer
+	flush := func() error {
+		if buf == nil {
+			return nil
+		}
+		sum := sha256.Sum256(buf.Bytes())
+		file := path.Join("pkg", name)
+		if strings.HasPrefix(name, "$") {
+			file = name[1:]
+		}
+		file += "." + hex.EncodeToString(sum[:8]) + ".js"
+		filename := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(filename), 0777); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, buf.Bytes(), 0666); err != nil {
+			return err
+		}
+		chunks = append(chunks, file)
+		return nil
+	}
+
+	err := s.writeProgramChunks(deps, func(chunk string) (*compiler.SourceMapFilter, error) {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		name, buf = chunk, new(bytes.Buffer)
+		return &compiler.SourceMapFilter{Writer: buf}, nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(chunks, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "manifest.json"), append(manifest, '\n'), 0666); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "loader.js"), []byte(fmt.Sprintf(chunkLoader, manifest)), 0666); err != nil {
+		return err
+	}
+
+	if s.options.Verify {
+		if err := verifyChunks(dir, chunks, deps[len(deps)-1].ImportPath); err != nil {
+			return err
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, chunk := range chunks {
+		used[chunk] = true
+	}
+	for _, chunk := range oldChunks {
+		if !used[chunk] && !strings.Contains(chunk, "..") {
+			os.Remove(filepath.Join(dir, filepath.FromSlash(chunk)))
+		}
+	}
+	return nil
+}
+
+// chunkLoader fetches the chunks listed in the manifest relative to its own
+// location, and runs their concatenation as a script.
+const chunkLoader = `(function() {
+  var base = document.currentScript.src.replace(/[^\/]*$/, "");
+  var chunks = %s;
+  Promise.all(chunks.map(function(chunk) {
+    return fetch(base + chunk).then(function(response) {
+      if (!response.ok) {
+        throw new Error("gopherjs: loading " + chunk + " failed: " + response.status + " " + response.statusText);
+      }
+      return response.text();
+    });
+  })).then(function(code) {
+    (0, eval)(code.join(""));
+  }).catch(function(err) {
+    console.error(err);
+  });
+})();
+`
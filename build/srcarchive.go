@@ -0,0 +1,191 @@
+package build
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SourceArchive is a zip or gzipped tar archive of package sources, laid out
+// like a GOPATH workspace with the sources under src/. Its contents appear in
+// the file system at Root, which is the absolute path of the archive file.
+type SourceArchive struct {
+	Root  string
+	files map[string]*archivedFile // by slash separated path relative to Root
+	dirs  map[string][]os.FileInfo
+}
+
+type archivedFile struct {
+	name    string
+	content []byte
+	modTime time.Time
+	isDir   bool
+}
+
+func (f *archivedFile) Name() string       { return f.name }
+func (f *archivedFile) Size() int64        { return int64(len(f.content)) }
+func (f *archivedFile) ModTime() time.Time { return f.modTime }
+func (f *archivedFile) IsDir() bool        { return f.isDir }
+func (f *archivedFile) Sys() interface{}   { return nil }
+
+func (f *archivedFile) Mode() os.FileMode {
+	if f.isDir {
+		return os.ModeDir | 0555
+	}
+	return 0444
+}
+
+// OpenSourceArchive reads the archive name, which must have one of the
+// extensions .zip, .tar.gz or .tgz.
+func OpenSourceArchive(name string) (*SourceArchive, error) {
+	root, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	a := &SourceArchive{
+		Root:  root,
+		files: make(map[string]*archivedFile),
+		dirs:  make(map[string][]os.FileInfo),
+	}
+
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		r, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		for _, f := range r.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			content, err := ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			a.add(f.Name, content, f.ModTime())
+		}
+	case strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"):
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+				continue
+			}
+			content, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			a.add(hdr.Name, content, hdr.ModTime)
+		}
+	default:
+		return nil, fmt.Errorf("source archive %s must be a .zip, .tar.gz or .tgz file", name)
+	}
+
+	for _, infos := range a.dirs {
+		sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	}
+	return a, nil
+}
+
+// add records the file with the given slash separated name, and its parent directories.
+func (a *SourceArchive) add(name string, content []byte, modTime time.Time) {
+	name = path.Clean(strings.TrimPrefix(name, "./"))
+	if _, ok := a.files[name]; ok {
+		return
+	}
+	a.files[name] = &archivedFile{name: path.Base(name), content: content, modTime: modTime}
+	for name != "." {
+		dir := path.Dir(name)
+		a.dirs[dir] = append(a.dirs[dir], a.files[name])
+		if _, ok := a.files[dir]; ok || dir == "." {
+			return
+		}
+		a.files[dir] = &archivedFile{name: path.Base(dir), modTime: modTime, isDir: true}
+		name = dir
+	}
+}
+
+// rel returns the slash separated path of name within the archive, and
+// whether name is inside the archive at all.
+func (a *SourceArchive) rel(name string) (string, bool) {
+	if name == a.Root {
+		return ".", true
+	}
+	if !strings.HasPrefix(name, a.Root+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(name[len(a.Root)+1:]), true
+}
+
+// mount makes the contents of the archive visible through the file system
+// hooks of bctx, and adds it to the front of the GOPATH workspaces. Paths
+// outside of the archive are handled by the hooks previously set, if any.
+func (a *SourceArchive) mount(bctx *build.Context) {
+	bctx.GOPATH = a.Root + string(filepath.ListSeparator) + bctx.GOPATH
+	outer := *bctx
+
+	bctx.OpenFile = func(name string) (io.ReadCloser, error) {
+		rel, ok := a.rel(name)
+		if !ok {
+			return openFile(&outer, name)
+		}
+		f, ok := a.files[rel]
+		if !ok || f.isDir {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		return ioutil.NopCloser(bytes.NewReader(f.content)), nil
+	}
+	bctx.ReadDir = func(dir string) ([]os.FileInfo, error) {
+		rel, ok := a.rel(dir)
+		if !ok {
+			return readDir(&outer, dir)
+		}
+		if f, ok := a.files[rel]; rel != "." && (!ok || !f.isDir) {
+			return nil, &os.PathError{Op: "readdir", Path: dir, Err: os.ErrNotExist}
+		}
+		return a.dirs[rel], nil
+	}
+	bctx.IsDir = func(name string) bool {
+		rel, ok := a.rel(name)
+		if !ok {
+			if outer.IsDir != nil {
+				return outer.IsDir(name)
+			}
+			fi, err := os.Stat(name)
+			return err == nil && fi.IsDir()
+		}
+		f, ok := a.files[rel]
+		return rel == "." || ok && f.isDir
+	}
+}
@@ -0,0 +1,59 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gopherjs/gopherjs/compiler"
+)
+
+// stdlibCacheObj returns the name of the prebuilt archive of pkg in the
+// standard library cache, or "" if pkg is not a standard library package or
+// the cache has no archive for it.
+//
+// The archives of the cache are keyed on the compiler version, which
+// implies the version of Go, and on the import path, in the layout of
+// PkgDir: DIR/VERSION/SUFFIX/IMPORTPATH.a, where SUFFIX is the install
+// suffix of the session. Packages built for tests or coverage, or with
+// ForceRebuild, are never taken from the cache.
+func (s *Session) stdlibCacheObj(pkg *PackageData) string {
+	if s.options.StdlibCache == "" || s.options.ForceRebuild || !pkg.Goroot || pkg.IsTest || pkg.CoverMode != "" {
+		return ""
+	}
+	name := filepath.Join(s.options.StdlibCache, compiler.Version, s.InstallSuffix(), filepath.FromSlash(pkg.ImportPath)+".a")
+	if fileInfo, err := os.Stat(name); err != nil || fileInfo.IsDir() {
+		return ""
+	}
+	return name
+}
+
+// loadStdlibCache loads the archive of pkg from the file name of the standard
+// library cache, after its imports, which are usually cached as well.
+func (s *Session) loadStdlibCache(pkg *PackageData, name string) (*compiler.Archive, error) {
+	for _, importedPkgPath := range pkg.Imports {
+		if importedPkgPath == "unsafe" {
+			continue
+		}
+		if _, _, err := s.buildImportPathWithSrcDir(importedPkgPath, pkg.Dir); err != nil {
+			return nil, err
+		}
+	}
+	if s.options.DryRun {
+		s.reportDryRun(pkg, true)
+		return nil, nil
+	}
+
+	objFile, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer objFile.Close()
+
+	archive, err := compiler.ReadArchive(name, pkg.ImportPath, objFile, s.Types)
+	if err != nil {
+		return nil, err
+	}
+	pkg.UpToDate = true
+	s.Archives[pkg.ImportPath] = archive
+	return archive, nil
+}
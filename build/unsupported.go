@@ -0,0 +1,130 @@
+package build
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"unicode"
+)
+
+// cgoPlaceholder is the type that references to package C that remain outside
+// of function bodies are replaced with by stubCgoFile.
+const cgoPlaceholder = "_Ctype_unsupported"
+
+// importsC reports whether file imports package "C", i.e. uses cgo.
+func importsC(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path == "C" {
+			return true
+		}
+	}
+	return false
+}
+
+// stubCgoFile reparses the source src of file, which uses cgo, for
+// options.AllowUnsupported: the bodies of its functions are replaced with a
+// panic describing the function that is not supported, and other references
+// to package C with cgoPlaceholder, which is declared in the file if
+// declarePlaceholder is set. Imports that are no longer used become blank
+// imports. The resulting file compiles as long as the package doesn't use
+// values from package C outside of function bodies.
+func stubCgoFile(fileSet *token.FileSet, file *ast.File, src []byte, importPath string, declarePlaceholder bool) (*ast.File, error) {
+	tokenFile := fileSet.File(file.Pos())
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	var bodies []*ast.BlockStmt
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path == "C" {
+			edits = append(edits, edit{tokenFile.Offset(spec.Pos()), tokenFile.Offset(spec.End()), `_ "unsafe"`})
+		}
+	}
+	for _, decl := range file.Decls {
+		d, ok := decl.(*ast.FuncDecl)
+		if !ok || d.Body == nil {
+			continue
+		}
+		name := d.Name.Name
+		if d.Recv != nil && len(d.Recv.List) != 0 {
+			recv := d.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok {
+				name = id.Name + "." + name
+			}
+		}
+		msg := fmt.Sprintf("%s.%s is not supported by GopherJS, it is implemented using cgo", importPath, name)
+		edits = append(edits, edit{tokenFile.Offset(d.Body.Pos()), tokenFile.Offset(d.Body.End()), "{ panic(" + strconv.Quote(msg) + ") }"})
+		bodies = append(bodies, d.Body)
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		for _, body := range bodies {
+			if n == body {
+				return false
+			}
+		}
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == "C" && id.Obj == nil {
+				edits = append(edits, edit{tokenFile.Offset(sel.Pos()), tokenFile.Offset(sel.End()), cgoPlaceholder})
+				return false
+			}
+		}
+		return true
+	})
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var stubbed []byte
+	last := 0
+	for _, e := range edits {
+		stubbed = append(stubbed, src[last:e.start]...)
+		stubbed = append(stubbed, e.text...)
+		last = e.end
+	}
+	stubbed = append(stubbed, src[last:]...)
+	if declarePlaceholder {
+		stubbed = append(stubbed, "\ntype "+cgoPlaceholder+" struct{}\n"...)
+	}
+
+	stub, err := parser.ParseFile(fileSet, tokenFile.Name(), stubbed, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	ast.Inspect(stub, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	for _, spec := range stub.Imports {
+		impPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(impPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else if !isIdentifier(name) {
+			continue // The package name is unknown.
+		}
+		if name != "_" && name != "." && !used[name] {
+			spec.Name = ast.NewIdent("_")
+		}
+	}
+	return stub, nil
+}
+
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,42 @@
+package build
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// vcsRevisionVar is the package level variable of a main package that is set
+// to the VCS revision of the package with options.BuildVCS.
+const vcsRevisionVar = "vcsRevision"
+
+// vcsRevision returns the git commit checked out in the directory dir, or ""
+// if dir is not in a git repository or git is not installed.
+func vcsRevision(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// stampVCS sets the initial value of the variable vcsRevision of the main
+// package pkg to its VCS revision, which is left empty outside of a git
+// repository. Like for options.Embed, the variable must be declared without
+// an initial value. Packages that don't declare it are left unchanged.
+func stampVCS(pkg *PackageData, files []*ast.File) error {
+	spec := findVarSpec(files, vcsRevisionVar)
+	if spec == nil {
+		return nil
+	}
+	if len(spec.Names) != 1 || len(spec.Values) != 0 {
+		return fmt.Errorf("buildvcs: variable %s must be declared on its own and without a value", vcsRevisionVar)
+	}
+	spec.Values = []ast.Expr{&ast.BasicLit{ValuePos: spec.Names[0].End(), Kind: token.STRING, Value: strconv.Quote(vcsRevision(pkg.Dir))}}
+	return nil
+}
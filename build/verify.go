@@ -0,0 +1,96 @@
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// verifyContext is the number of lines shown before and after the error
+// location by verifyJS.
+const verifyContext = 3
+
+var nodeCheckLocation = regexp.MustCompile(`(?m)^.*:(\d+)$`)
+
+// verifyJS checks that the JavaScript file filename, written for the program
+// name, parses, using "node --check". Invalid output is the fault of the
+// compiler, so the returned error asks for a bug report and shows the code
+// around the location of the syntax error.
+func verifyJS(filename, name string) error {
+	node, err := NodeExecutable()
+	if err != nil {
+		return fmt.Errorf("verifying the output requires Node.js: %v", err)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(node, "--check", filename)
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*exec.ExitError); !ok {
+		return fmt.Errorf("verifying the output requires Node.js: %v", err)
+	}
+
+	output := strings.TrimSpace(stderr.String())
+	msg := fmt.Sprintf("internal compiler error: the JavaScript generated for %s doesn't parse, please report this as a GopherJS bug:\n%s", name, output)
+	m := nodeCheckLocation.FindStringSubmatch(output)
+	if m == nil {
+		return fmt.Errorf("%s", msg)
+	}
+	line, _ := strconv.Atoi(m[1])
+	code, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("%s", msg)
+	}
+	return fmt.Errorf("%s\n\n%s", msg, snippet(code, line))
+}
+
+// snippet returns the lines of code around line, which is 1-based, with line
+// numbers and the line itself marked.
+func snippet(code []byte, line int) string {
+	lines := strings.Split(string(code), "\n")
+	var buf bytes.Buffer
+	for i := line - verifyContext; i <= line+verifyContext; i++ {
+		if i < 1 || i > len(lines) {
+			continue
+		}
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&buf, "%s%6d  %s\n", marker, i, lines[i-1])
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// verifyChunks verifies the program written into dir by writeCommandChunks,
+// through a temporary file holding the concatenation of its chunks.
+func verifyChunks(dir string, chunks []string, name string) error {
+	f, err := ioutil.TempFile("", "gopherjs-verify")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	for _, chunk := range chunks {
+		code, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(chunk)))
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.Write(code); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return verifyJS(f.Name(), name)
+}
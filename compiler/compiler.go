@@ -5,10 +5,13 @@ import (
 	"encoding/binary"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go/scanner"
 	"go/token"
 	"go/types"
 	"io"
+	"regexp"
 	"strings"
 
 	"github.com/gopherjs/gopherjs/compiler/prelude"
@@ -31,6 +34,22 @@ func (err ErrorList) Error() string {
 	return err[0].Error()
 }
 
+// Truncate returns the first n errors of err. If err has more than n errors,
+// a "too many errors" error is appended, positioned at the last error kept.
+func (err ErrorList) Truncate(n int) ErrorList {
+	if len(err) <= n || n <= 0 {
+		return err
+	}
+	var tooMany error = errors.New("too many errors")
+	switch last := err[n-1].(type) {
+	case types.Error:
+		tooMany = types.Error{Fset: last.Fset, Pos: last.Pos, Msg: "too many errors"}
+	case *scanner.Error:
+		tooMany = &scanner.Error{Pos: last.Pos, Msg: "too many errors"}
+	}
+	return append(err[:n:n], tooMany)
+}
+
 type Archive struct {
 	ImportPath   string
 	Name         string
@@ -40,6 +59,9 @@ type Archive struct {
 	IncJSCode    []byte
 	FileSet      []byte
 	Minified     bool
+	// Inlines holds the templates for inlining calls of the functions of
+	// the package, by full name.
+	Inlines map[string]string
 }
 
 type Decl struct {
@@ -103,6 +125,21 @@ type dceInfo struct {
 }
 
 func WriteProgramCode(pkgs []*Archive, w *SourceMapFilter) error {
+	return WriteProgramChunks(pkgs, func(name string) (*SourceMapFilter, error) { return w, nil })
+}
+
+// Names of the chunks written by WriteProgramChunks that don't belong to a package.
+const (
+	PreludeChunk = "$prelude"
+	StartChunk   = "$start"
+)
+
+// WriteProgramChunks writes the program made of pkgs like WriteProgramCode,
+// split into chunks: the prelude, the code of each package and the code that
+// starts the program. Before each chunk, the writer for it is obtained by
+// calling chunk with PreludeChunk, the import path of the package or
+// StartChunk. The program is the concatenation of all chunks in this order.
+func WriteProgramChunks(pkgs []*Archive, chunk func(name string) (*SourceMapFilter, error)) error {
 	mainPkg := pkgs[len(pkgs)-1]
 	minify := mainPkg.Minified
 
@@ -151,30 +188,126 @@ func WriteProgramCode(pkgs []*Archive, w *SourceMapFilter) error {
 		}
 	}
 
-	if _, err := w.Write([]byte("\"use strict\";\n(function() {\n\n")); err != nil {
+	w, err := chunk(PreludeChunk)
+	if err != nil {
 		return err
 	}
-	if _, err := w.Write(removeWhitespace([]byte(prelude.Prelude), minify)); err != nil {
+	// The directive is placed inside the function, so that it stays in effect when code is prepended to the output.
+	if _, err := w.Write([]byte("(function() {\n\"use strict\";\n\n")); err != nil {
 		return err
 	}
+	if _, err := w.Write(removeWhitespace([]byte(prelude.Core), minify)); err != nil {
+		return err
+	}
+	scheduler := false
+	for _, feature := range usedPreludeFeatures(pkgs, dceSelection) {
+		if _, err := w.Write(removeWhitespace([]byte(feature.Code), minify)); err != nil {
+			return err
+		}
+		scheduler = scheduler || feature.Defines("$go")
+	}
 	if _, err := w.Write([]byte("\n")); err != nil {
 		return err
 	}
 
 	// write packages
 	for _, pkg := range pkgs {
+		w, err := chunk(pkg.ImportPath)
+		if err != nil {
+			return err
+		}
 		if err := WritePkgCode(pkg, dceSelection, minify, w); err != nil {
 			return err
 		}
 	}
 
-	if _, err := w.Write([]byte("$synthesizeMethods();\nvar $mainPkg = $packages[\"" + string(mainPkg.ImportPath) + "\"];\n$packages[\"runtime\"].$init();\n$go($mainPkg.$init, []);\n$flushConsole();\n\n}).call(this);\n")); err != nil {
+	w, err = chunk(StartChunk)
+	if err != nil {
+		return err
+	}
+	runMain := "$go($mainPkg.$init, []);\n"
+	if !scheduler {
+		runMain = runMainWithoutScheduler
+	}
+	if _, err := w.Write([]byte("$synthesizeMethods();\n$mainPkg = $packages[\"" + string(mainPkg.ImportPath) + "\"];\n$packages[\"runtime\"].$init();\n" + runMain + "$flushConsole();\n\n}).call(this);\n")); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// runMainWithoutScheduler initializes the main package of programs without
+// the scheduler of the prelude, which can't block, right on the stack. A panic
+// or runtime.Goexit ends the program like in the goroutine started by $go.
+const runMainWithoutScheduler = `try {
+  $mainPkg.$init();
+} catch (err) {
+  if (!$noGoroutine.exit) {
+    if ($global.process !== undefined) {
+      $flushConsole();
+      console.error($panicReport(err));
+      $global.process.exit(2);
+    }
+    throw err;
+  }
+  if ($checkForDeadlock) {
+    console.error("fatal error: all goroutines are asleep - deadlock!");
+    if ($global.process !== undefined) {
+      $global.process.exit(2);
+    }
+  }
+}
+`
+
+var preludeNameRegexp = regexp.MustCompile(`\$[A-Za-z0-9_]+`)
+
+// usedPreludeFeatures returns the optional parts of the prelude that are
+// referred to by the code of the declarations in dceSelection, or by the code
+// of other used features.
+func usedPreludeFeatures(pkgs []*Archive, dceSelection map[*Decl]struct{}) []prelude.Feature {
+	names := make(map[string]bool)
+	collect := func(code []byte) {
+		for _, name := range preludeNameRegexp.FindAll(code, -1) {
+			names[string(name)] = true
+		}
+	}
+	for _, pkg := range pkgs {
+		collect(pkg.IncJSCode)
+		for _, d := range pkg.Declarations {
+			if _, ok := dceSelection[d]; ok {
+				collect(d.DeclCode)
+				collect(d.MethodListCode)
+				collect(d.TypeInitCode)
+				collect(d.InitCode)
+			}
+		}
+	}
+
+	isUsed := make([]bool, len(prelude.Features))
+	for changed := true; changed; {
+		changed = false
+		for i, feature := range prelude.Features {
+			if isUsed[i] {
+				continue
+			}
+			for _, name := range feature.Names {
+				if names[name] {
+					isUsed[i], changed = true, true
+					collect([]byte(feature.Code))
+					break
+				}
+			}
+		}
+	}
+	var used []prelude.Feature
+	for i, feature := range prelude.Features {
+		if isUsed[i] {
+			used = append(used, feature)
+		}
+	}
+	return used
+}
+
 func WritePkgCode(pkg *Archive, dceSelection map[*Decl]struct{}, minify bool, w *SourceMapFilter) error {
 	if w.MappingCallback != nil && pkg.FileSet != nil {
 		w.fileSet = token.NewFileSet()
@@ -185,6 +318,9 @@ func WritePkgCode(pkg *Archive, dceSelection map[*Decl]struct{}, minify bool, w
 	if _, err := w.Write(pkg.IncJSCode); err != nil {
 		return err
 	}
+	// The package function is called right away rather than on first use: the
+	// type definitions of importing packages read $packages[path] while their
+	// own functions run, and the $init chain from main reaches every package anyway.
 	if _, err := w.Write(removeWhitespace([]byte(fmt.Sprintf("$packages[\"%s\"] = (function() {\n", pkg.ImportPath)), minify)); err != nil {
 		return err
 	}
@@ -374,6 +374,9 @@ func (c *funcContext) translateExpr(expr ast.Expr) *expression {
 				if v := c.p.Types[e.Y].Value; v != nil {
 					i, _ := constant.Uint64Val(constant.ToInt(v))
 					if i >= 32 {
+						if e.Op == token.SHR && !isUnsigned(basic) {
+							return c.fixNumber(c.formatParenExpr("%e >> 31", e.X), basic)
+						}
 						return c.formatExpr("0")
 					}
 					return c.fixNumber(c.formatExpr("%e %s %s", e.X, op, strconv.FormatUint(i, 10)), basic)
@@ -508,6 +511,9 @@ func (c *funcContext) translateExpr(expr ast.Expr) *expression {
 		sel, ok := c.p.SelectionOf(e)
 		if !ok {
 			// qualified identifier
+			if name := c.raceName(e); name != "" {
+				return c.formatExpr("($raceRead(%s), %s)", name, c.objectName(obj))
+			}
 			return c.formatExpr("%s", c.objectName(obj))
 		}
 
@@ -522,15 +528,15 @@ func (c *funcContext) translateExpr(expr ast.Expr) *expression {
 			}
 			return c.formatExpr("%e.%s", e.X, strings.Join(fields, "."))
 		case types.MethodVal:
-			return c.formatExpr(`$methodVal(%s, "%s")`, c.makeReceiver(e), sel.Obj().(*types.Func).Name())
+			return c.formatExpr(`$methodVal(%s, "%s")`, c.makeReceiver(e), methodProp(sel.Obj()))
 		case types.MethodExpr:
 			if !sel.Obj().Exported() {
 				c.p.dependencies[sel.Obj()] = true
 			}
 			if _, ok := sel.Recv().Underlying().(*types.Interface); ok {
-				return c.formatExpr(`$ifaceMethodExpr("%s")`, sel.Obj().(*types.Func).Name())
+				return c.formatExpr(`$ifaceMethodExpr("%s")`, methodProp(sel.Obj()))
 			}
-			return c.formatExpr(`$methodExpr(%s, "%s")`, c.typeName(sel.Recv()), sel.Obj().(*types.Func).Name())
+			return c.formatExpr(`$methodExpr(%s, "%s")`, c.typeName(sel.Recv()), methodProp(sel.Obj()))
 		default:
 			panic(fmt.Sprintf("unexpected sel.Kind(): %T", sel.Kind()))
 		}
@@ -553,6 +559,11 @@ func (c *funcContext) translateExpr(expr ast.Expr) *expression {
 			if typesutil.IsJsPackage(obj.Pkg()) && obj.Name() == "InternalObject" {
 				return c.translateExpr(e.Args[0])
 			}
+			if fun, ok := obj.(*types.Func); ok {
+				if inlined := c.inlinedCall(e, sig, fun, nil); inlined != nil {
+					return inlined
+				}
+			}
 			return c.translateCall(e, sig, c.translateExpr(f))
 
 		case *ast.SelectorExpr:
@@ -568,6 +579,11 @@ func (c *funcContext) translateExpr(expr ast.Expr) *expression {
 						return c.translateExpr(e.Args[0])
 					}
 				}
+				if fun, ok := obj.(*types.Func); ok {
+					if inlined := c.inlinedCall(e, sig, fun, nil); inlined != nil {
+						return inlined
+					}
+				}
 				return c.translateCall(e, sig, c.translateExpr(f))
 			}
 
@@ -660,11 +676,13 @@ func (c *funcContext) translateExpr(expr ast.Expr) *expression {
 					}
 				}
 
-				methodName := sel.Obj().Name()
-				if reservedKeywords[methodName] {
-					methodName += "$"
+				if _, isInterface := declaredFuncRecv.Underlying().(*types.Interface); !isInterface {
+					if inlined := c.inlinedCall(e, sig, sel.Obj().(*types.Func), recv); inlined != nil {
+						return inlined
+					}
 				}
-				return c.translateCall(e, sig, c.formatExpr("%s.%s", recv, methodName))
+
+				return c.translateCall(e, sig, c.formatExpr("%s.%s", recv, methodProp(sel.Obj())))
 
 			case types.FieldVal:
 				fields, jsTag := c.translateSelection(sel, f.Pos())
@@ -724,6 +742,9 @@ func (c *funcContext) translateExpr(expr ast.Expr) *expression {
 		}
 		switch o := obj.(type) {
 		case *types.Var, *types.Const:
+			if name := c.raceName(e); name != "" {
+				return c.formatExpr("($raceRead(%s), %s)", name, c.objectName(o))
+			}
 			return c.formatExpr("%s", c.objectName(o))
 		case *types.Func:
 			return c.formatExpr("%s", c.objectName(o))
@@ -990,6 +1011,10 @@ func (c *funcContext) translateConversion(expr ast.Expr, desiredType types.Type)
 				}
 				return c.fixNumber(c.formatExpr("%s.$low", c.translateExpr(expr)), t)
 			case isFloat(basicExprType):
+				if isUnsigned(t) {
+					// Values of 1<<31 and above don't fit into signed 32 bits.
+					return c.formatParenExpr("%e >>> 0", expr)
+				}
 				return c.formatParenExpr("%e >> 0", expr)
 			case types.Identical(exprType, types.Typ[types.UnsafePointer]):
 				return c.translateExpr(expr)
@@ -1008,7 +1033,8 @@ func (c *funcContext) translateConversion(expr ast.Expr, desiredType types.Type)
 			switch et := exprType.Underlying().(type) {
 			case *types.Basic:
 				if is64Bit(et) {
-					value = c.formatExpr("%s.$low", value)
+					// Values that don't fit into 32 bits are never valid code points.
+					return c.formatExpr("$encodeRune(%1h === 0 ? %1l : -1)", expr)
 				}
 				if isNumeric(et) {
 					return c.formatExpr("$encodeRune(%s)", value)
@@ -1023,6 +1049,10 @@ func (c *funcContext) translateConversion(expr ast.Expr, desiredType types.Type)
 				panic(fmt.Sprintf("Unhandled conversion: %v\n", et))
 			}
 		case t.Kind() == types.UnsafePointer:
+			if _, isBinary := astutil.RemoveParens(expr).(*ast.BinaryExpr); isBinary {
+				// Pointers are not numbers in JavaScript. This can't be a compile error, since unused code of the standard library does it.
+				return c.formatExpr(`$throwRuntimeError("unsafe.Pointer arithmetic is not supported")`)
+			}
 			if unary, isUnary := expr.(*ast.UnaryExpr); isUnary && unary.Op == token.AND {
 				if indexExpr, isIndexExpr := unary.X.(*ast.IndexExpr); isIndexExpr {
 					return c.formatExpr("$sliceToArray(%s)", c.translateConversionToSlice(indexExpr.X, types.NewSlice(types.Typ[types.Uint8])))
@@ -0,0 +1,261 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/compiler/astutil"
+	"github.com/gopherjs/gopherjs/compiler/prelude"
+	"github.com/gopherjs/gopherjs/compiler/typesutil"
+)
+
+// Inlining of small functions is enabled by optimization level 1 and above.
+//
+// A function or method can be inlined if its body is a single return statement
+// of an expression of basic type that only uses its parameters, each exactly
+// once and in the order of declaration (with the receiver first), constants,
+// field selections and operators. Such an expression has no side effects of
+// its own and evaluates the arguments in the same order as a call, so a call
+// can be replaced by the expression with the parameters substituted by the
+// arguments. The operators && and || are excluded, since they may skip the
+// evaluation of an argument, and so are dereferences of pointers, explicit or
+// by field selections, that precede parameters: a call evaluates all of its
+// arguments before the body can panic or read through a pointer.
+//
+// The JavaScript of the expression is kept as a template in which the
+// parameters appear as placeholders, in the archive of the package, so that
+// calls in other packages can be inlined as well.
+
+// inlinePlaceholder returns the name that parameter i has in inline templates.
+func inlinePlaceholder(i int) string {
+	return "$inline" + strconv.Itoa(i) + "$"
+}
+
+// inlineTemplate returns the inline template of the function fun, or false if
+// it can't be inlined.
+func (c *funcContext) inlineTemplate(fun *ast.FuncDecl) (string, bool) {
+	o := c.p.Defs[fun.Name].(*types.Func)
+	sig := o.Type().(*types.Signature)
+	if fun.Body == nil || len(fun.Body.List) != 1 || sig.Variadic() || sig.Results().Len() != 1 {
+		return "", false
+	}
+	if _, isIntrinsic := prelude.Intrinsics[o.FullName()]; isIntrinsic && fun.Recv == nil {
+		return "", false // The body is not used.
+	}
+	ret, ok := fun.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	result := ret.Results[0]
+	resultType := sig.Results().At(0).Type()
+	if _, isBasic := resultType.Underlying().(*types.Basic); !isBasic || !types.Identical(c.p.TypeOf(result), resultType) {
+		return "", false
+	}
+
+	var idents []*ast.Ident
+	if fun.Recv != nil {
+		idents = append(idents, fun.Recv.List[0].Names...)
+	}
+	for _, field := range fun.Type.Params.List {
+		idents = append(idents, field.Names...)
+	}
+	n := sig.Params().Len()
+	if sig.Recv() != nil {
+		n++
+	}
+	if len(idents) != n {
+		return "", false // unnamed parameters
+	}
+	var params []*types.Var
+	for _, ident := range idents {
+		param, ok := c.p.Defs[ident].(*types.Var)
+		if !ok {
+			return "", false
+		}
+		params = append(params, param)
+	}
+
+	next := 0
+	inlinable := true
+	var check func(e ast.Expr)
+	check = func(e ast.Expr) {
+		if !inlinable {
+			return
+		}
+		if b, ok := c.p.TypeOf(e).Underlying().(*types.Basic); ok && (is64Bit(b) || isComplex(b)) {
+			if _, isNamed := c.p.TypeOf(e).(*types.Named); isNamed {
+				// Values of these types are constructed by their type, whose
+				// name is only valid in the package that declares it.
+				inlinable = false
+				return
+			}
+		}
+		if c.p.Types[e].Value != nil {
+			return // Constants are translated to literals.
+		}
+		isBasic := func(e ast.Expr) bool {
+			_, ok := c.p.TypeOf(e).Underlying().(*types.Basic)
+			return ok
+		}
+		switch e := e.(type) {
+		case *ast.ParenExpr:
+			check(e.X)
+		case *ast.Ident:
+			param, ok := c.p.Uses[e].(*types.Var)
+			if !ok || next >= len(params) || params[next] != param {
+				inlinable = false
+				return
+			}
+			next++
+		case *ast.SelectorExpr:
+			sel, ok := c.p.SelectionOf(e)
+			if !ok || sel.Kind() != types.FieldVal || selectsJsField(sel) {
+				inlinable = false
+				return
+			}
+			check(e.X)
+			if selectsThroughPointer(sel) && next != len(params) {
+				inlinable = false
+			}
+		case *ast.StarExpr:
+			check(e.X)
+			if next != len(params) {
+				inlinable = false
+			}
+		case *ast.UnaryExpr:
+			switch e.Op {
+			case token.ADD, token.SUB, token.XOR, token.NOT:
+				inlinable = isBasic(e.X)
+				check(e.X)
+			default:
+				inlinable = false
+			}
+		case *ast.BinaryExpr:
+			if e.Op == token.LAND || e.Op == token.LOR {
+				inlinable = false
+				return
+			}
+			inlinable = isBasic(e.X) && isBasic(e.Y)
+			check(e.X)
+			check(e.Y)
+		default:
+			inlinable = false
+		}
+	}
+	check(astutil.RemoveParens(result))
+	if !inlinable || next != len(params) {
+		return "", false
+	}
+
+	inner := &funcContext{
+		FuncInfo:    c.p.FuncDeclInfos[o],
+		p:           c.p,
+		parent:      c,
+		sig:         sig,
+		allVars:     make(map[string]int, len(c.allVars)),
+		localVars:   []string{},
+		flowDatas:   map[*types.Label]*flowData{nil: {}},
+		caseCounter: 1,
+		labelCases:  make(map[*types.Label]int),
+	}
+	for k, v := range c.allVars {
+		inner.allVars[k] = v
+	}
+	for i, param := range params {
+		c.p.objectNames[param] = inlinePlaceholder(i)
+	}
+	var template string
+	output := inner.CatchOutput(0, func() {
+		template = inner.translateExpr(result).String()
+	})
+	for _, param := range params {
+		delete(c.p.objectNames, param)
+	}
+	if len(output) != 0 || len(inner.localVars) != 0 {
+		return "", false
+	}
+	for i := range params {
+		if strings.Count(template, inlinePlaceholder(i)) != 1 {
+			return "", false
+		}
+	}
+	return template, true
+}
+
+// selectsJsField reports whether sel selects a field through a *js.Object, or
+// a field with a js tag, which are accessed through externalization.
+func selectsJsField(sel selection) bool {
+	t := sel.Recv()
+	for _, index := range sel.Index() {
+		if ptr, ok := t.Underlying().(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		s, ok := t.Underlying().(*types.Struct)
+		if !ok {
+			return true
+		}
+		if index < s.NumFields() && (getJsTag(s.Tag(index)) != "" || typesutil.IsJsObject(s.Field(index).Type())) {
+			return true
+		}
+		t = s.Field(index).Type()
+	}
+	return false
+}
+
+// selectsThroughPointer reports whether sel dereferences a pointer, the
+// receiver or an embedded field, to select its field.
+func selectsThroughPointer(sel selection) bool {
+	t := sel.Recv()
+	for _, index := range sel.Index() {
+		if _, ok := t.Underlying().(*types.Pointer); ok {
+			return true
+		}
+		s, ok := t.Underlying().(*types.Struct)
+		if !ok || index >= s.NumFields() {
+			return false
+		}
+		t = s.Field(index).Type()
+	}
+	return false
+}
+
+// inlinedCall returns the call e of fun, with the signature sig and the
+// receiver recv if fun is a method, inlined, or nil if fun can't be inlined.
+func (c *funcContext) inlinedCall(e *ast.CallExpr, sig *types.Signature, fun *types.Func, recv *expression) *expression {
+	if c.p.optLevel < 1 || c.Blocking[e] || e.Ellipsis.IsValid() || fun.Pkg() == nil {
+		return nil
+	}
+	var template string
+	if fun.Pkg() == c.p.Pkg {
+		t, ok := c.p.inlines[fun]
+		if !ok {
+			return nil
+		}
+		template = t
+	} else {
+		archive, err := c.p.importContext.Import(fun.Pkg().Path())
+		if err != nil {
+			return nil
+		}
+		t, ok := archive.Inlines[fun.FullName()]
+		if !ok {
+			return nil
+		}
+		template = t
+	}
+
+	args := c.translateArgs(sig, e.Args, false)
+	if recv != nil {
+		args = append([]string{recv.String()}, args...)
+	}
+	// The placeholders are replaced in one pass, so that the code of an
+	// argument is never mistaken for the placeholder of a later one.
+	pairs := make([]string, 0, 2*len(args))
+	for i, arg := range args {
+		pairs = append(pairs, inlinePlaceholder(i), "("+arg+")")
+	}
+	return c.formatParenExpr("%s", strings.NewReplacer(pairs...).Replace(template))
+}
@@ -224,10 +224,10 @@ var FS = func() http.FileSystem {
 		},
 		"/src/math/math.go": &vfsgen۰CompressedFileInfo{
 			name:             "math.go",
-			modTime:          mustUnmarshalTextTime("2017-06-28T23:32:11Z"),
-			uncompressedSize: 4709,
+			modTime:          mustUnmarshalTextTime("2026-10-14T11:17:48Z"),
+			uncompressedSize: 3630,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x57\x6f\x6f\xa3\xb8\x13\x7e\x1d\x3e\xc5\xfc\xa2\x9f\x56\x70\x4b\xfe\x40\xab\x6a\xb5\x6a\x2a\xed\xf5\xb6\x7b\x95\xda\xde\xe9\xda\xbb\x37\x55\x5e\x18\x62\x27\xce\x82\x4d\x8d\xd9\x92\x6d\xfb\xdd\x4f\x36\x10\x0c\x81\x26\xb9\x77\x60\x3f\xf3\xcc\x33\xc3\x8c\x19\x4f\x26\xf0\x31\xc8\x68\xb4\x80\x75\x6a\x59\x09\x0a\xbf\xa3\x25\x86\x18\xc9\x95\x65\xd1\x38\xe1\x42\x82\x6d\x0d\x86\x4b\x2a\x57\x59\x30\x0e\x79\x3c\x59\xf2\x64\x85\xc5\x3a\xad\x1f\xd6\xe9\xd0\x72\x2c\xeb\x07\x12\xda\x10\x66\xb0\x4e\xc7\xdf\x22\x1e\xa0\x68\xfc\x0d\x4b\x7b\x78\x8b\xe4\x6a\xe8\x68\xc0\x4f\x2c\x38\x90\x88\x23\x79\x76\x0a\x33\x98\xea\xc5\x84\xa7\xd7\x8c\xc0\x0c\x3c\x98\x68\x84\x5e\x65\x78\x59\xac\x8e\x9a\xcb\x88\x29\xc3\x6a\xc9\x22\x19\x0b\xe1\x4b\xc8\x53\x3b\xaf\x88\x9d\xad\x87\x17\x6b\x20\xb0\xcc\x04\xd3\xca\xc6\x97\x28\x8a\xec\x21\x0a\x79\x3a\x74\x21\x77\xc6\x57\x0a\x66\x3b\xd6\x9b\x41\xb3\x3a\x8a\x67\xd5\x43\x94\x52\x76\x38\x4f\x4a\x59\x3f\xcd\x11\x7a\x14\xba\x87\x48\xa2\x23\xf4\x48\xd4\xa7\x47\xa2\x63\xf4\x28\x74\x3f\x91\x6f\x6f\x5c\x38\x86\xcb\x1f\xba\xb0\xe9\xa4\xbb\x0c\x84\x3c\x58\x56\x18\x08\xd9\xad\xea\x12\xd3\xe8\x70\x1a\x4c\xa3\x1e\x1a\x9e\x6c\x52\xba\x64\x76\xee\xc2\xa6\x93\x8d\x12\xb0\x73\x38\x87\x29\xbc\xbe\x82\x37\xc9\x61\x36\x2b\xcb\xdd\x81\xff\xcd\xc0\xde\xd4\x7b\x1b\x73\xef\xc5\x1a\x54\x4a\x46\xb9\x35\x78\xdb\xea\xca\x0d\xe7\x87\x37\x42\x6f\x1f\x5c\x1e\xd3\x06\xfd\x5d\xf0\x1b\x8d\xfb\x73\x50\x12\x2d\x4a\x4c\x6d\xf5\x55\x90\xf7\x7d\x63\x05\x68\xe0\xc3\xbd\x06\x61\xc3\x22\x4f\x0e\x8e\x0e\xe7\x49\x77\x70\x5f\xf3\xc4\x3f\x98\x25\xe1\xcf\x43\x17\xfc\x3e\xa2\xd8\xdb\x13\x40\x01\xa9\x6d\xae\x22\xce\xc5\xc1\xde\x89\x42\x77\x47\x71\x25\x70\x9e\xd8\xa4\x26\xb2\x89\x40\x61\xf5\xea\x2a\xcf\x40\x99\x74\x0c\x62\x52\x98\xd4\x1c\xbf\x6f\x12\x2e\xed\xc4\x85\xa7\xf7\xf4\xac\xb6\xa8\xda\xf2\x9a\x11\x5b\x75\x4a\xe1\xc2\xb0\x49\x9f\xa9\x0c\x57\xea\x29\x44\x29\x06\x8d\xb9\x98\xc1\xf4\x73\xdd\x00\xc5\x4f\xc3\x1a\x2c\x30\x41\x59\x24\x8d\x9d\xa2\x5b\x54\x7b\x6c\xfd\x28\x68\x1d\xa5\x0b\xb5\xd3\x80\xf3\xa8\x6c\x49\xa2\x5a\xad\xfc\x17\x19\x9d\xb6\x75\xae\x1b\xae\xc2\x95\x7f\xa7\x36\xee\xbc\xc2\x55\xc9\x42\x51\x8a\x0d\x1d\x77\xe8\xae\x91\x6d\x9a\x6a\x05\x8d\xfc\xaa\x23\x80\x6c\x6d\x6e\x16\x3a\xdd\xdd\x5f\xa5\x79\xa6\x68\xd0\x4c\xfd\x1c\x0d\x59\x6a\xb1\x52\xae\xec\x2e\x66\xe0\x4d\xfd\xd3\x36\x04\x7e\xe9\xac\x57\x6f\xea\x9f\x6c\xab\xa6\x07\x83\xf3\x64\xd4\xc0\x99\xee\xce\xd5\xff\xfb\x70\x7f\xa3\x03\x1d\x7e\xdc\x75\xb8\x9f\x1c\xe7\xc9\x6e\x07\xdc\xf0\x65\x4f\x23\x51\x02\xb9\xfa\x16\x39\xbc\xc0\x64\x02\xcf\x5c\x7c\x47\x82\x67\x6c\x01\x84\x0b\xe0\x89\xa4\x31\xfd\x89\x05\x04\xd9\x12\x28\x83\x7f\x3e\xb9\x20\x70\xcc\x7f\x60\x40\x12\x52\x1e\x63\x48\x38\x65\xd2\x28\x4c\xc4\x4c\xa5\x86\xc4\x88\x2f\xbb\xfb\xf3\x86\x2f\xbd\xe9\xfb\x8d\x1e\x15\x90\xa6\xcd\x9e\x03\x2e\x2a\x20\x0d\x9b\x3d\xa7\x59\xa4\x11\xb5\xc5\x2d\xca\xf7\x1e\xee\x71\x89\x31\xac\xe8\x3b\xbf\xc5\xca\xaa\xc4\x18\x56\x7c\xb1\xd7\xaa\x9e\x39\x8b\x94\xfe\x3f\xe6\x0b\x95\x53\x45\xb4\x93\xd6\x5b\xbe\x20\xcd\x53\xaf\x6a\xad\xed\xd2\xee\x99\xf0\xfa\xda\xd7\xfa\xc4\xdd\x7e\x5b\x4a\xc0\x9b\xf4\xc3\xf4\xb1\x34\xd0\x35\xfa\x79\xa6\xe3\x22\x2e\x78\x8e\xd1\xfc\x23\x5d\xc1\x6e\xd1\xb7\x95\x5e\x75\x6c\x74\x05\xad\xbc\x56\x98\x3f\xf9\xf3\xbb\x13\x87\x9e\x32\x3c\x15\x85\xad\x1f\x47\x1e\x7c\xf8\xa0\x66\x8d\x46\x84\xe6\xbc\xd1\x18\x38\xbc\x9e\xd2\x2d\xba\xab\x3b\xcd\x7f\xe1\x18\x51\xb6\xc0\x62\xef\xd7\x13\x0d\x64\xcd\x70\x4f\x97\x2c\xa0\x8d\xe1\xae\x3a\xb1\xab\xd1\xa7\x73\x8e\x32\x08\x0e\x1f\x7c\x7b\xe7\xf0\xfb\x63\xc6\xf0\xfe\x29\xfc\x9e\xb2\xd6\x3d\xc5\x4e\x29\x73\x21\xe4\x69\xa3\xee\x4a\x4e\x2d\xdd\x71\x8b\x91\xce\x60\x79\x3a\x62\xd8\x4d\x9f\xfa\x86\xdd\x87\x23\x6e\x04\xbd\x17\x82\x87\x63\xee\x03\xfd\xd7\x81\x07\x91\xb1\xbe\x19\xae\xaa\xdc\xba\x44\x8d\xcf\x5c\xbc\xea\x33\xba\x5d\x01\x66\xed\x36\x66\xe5\x92\xdb\xa6\x4c\xda\xb9\xa3\x45\xa8\x7b\x65\x90\x11\x48\xa5\xc8\x42\xa9\x2c\x33\xca\xe4\x89\x8f\x84\x40\x1b\x80\x47\x7f\x5e\xbc\x5b\x03\x6d\x5c\x6d\x3c\xfa\xf3\xf2\xbd\xdc\x38\x3b\x2d\x37\xbc\x79\xf9\xbe\x0d\x91\x32\xaa\x7e\x68\x2f\xd6\x00\x05\xaa\xf5\x5b\x57\xe4\x2f\xca\xee\xd7\x8c\x10\x2c\x86\xce\xf8\x0e\x3f\xdb\x9f\x1c\x6b\xb0\x4e\xc7\xd7\x4c\x62\xc1\x50\xf4\x47\xb0\xc6\xa1\xb4\x83\x8c\x38\xe3\x7b\x65\x61\x28\x1c\xba\x6d\xba\xbf\xf5\xa6\x26\x2d\xe9\x50\xe0\xec\x21\x34\x43\xdb\x65\xbc\x2a\x76\xff\x03\x65\x99\x94\x1e\xca\xb3\xd3\x1d\x4a\x63\xc8\x55\x2e\x03\x2a\xd3\xea\xac\x3e\xf1\x1d\x28\x02\x57\x99\x0c\x32\x32\x36\x55\x3f\x4e\xe7\xa0\x46\xa7\xea\x4b\xab\x7d\x23\x4d\x8f\xd3\x79\x9b\x9b\x08\x1e\x6b\xfe\xa0\xa4\x75\x2a\x3f\x15\x7f\xd3\x1e\x66\x10\x34\xe8\x5b\xee\x9b\xfc\x67\xa7\xa6\x76\x55\xd7\x8a\xad\x28\xeb\xad\x71\x99\x9e\xb6\xf6\x02\x69\xb7\x25\x78\x73\xe7\xfc\xfc\xc4\x87\x8f\x7d\x80\xe9\xdc\x69\x8b\x68\x05\xd9\xea\xaf\xce\x20\x8b\x05\x3b\x70\x76\xf7\x3d\x73\x1f\x2e\x2e\xe0\xc4\x77\x76\x53\x52\x47\x65\xbd\x59\xff\x06\x00\x00\xff\xff\x5c\x1e\x94\x31\x65\x12\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x57\x5d\x8f\x9c\x36\x14\x7d\x1e\x7e\xc5\xed\xa8\x8a\xa0\x61\x3e\x98\x46\x55\x15\x85\x48\xd5\x36\x49\x23\xed\x46\x55\x77\xd5\x77\x0f\xd8\xe0\x0d\xd8\xae\xf1\x64\x99\xec\xe6\xbf\x57\x36\x5f\x66\x06\x86\xe1\x65\xc5\x98\x73\xce\xbd\xb6\xef\xbd\x9c\xdd\x6c\xe0\xf5\xfe\x40\xb3\x18\x1e\x0b\xc7\x11\x28\xfa\x8a\x12\x0c\x39\x52\xa9\xe3\xd0\x5c\x70\xa9\xc0\x75\x16\xcb\x84\xaa\xf4\xb0\x5f\x47\x3c\xdf\x24\x5c\xa4\x58\x3e\x16\xdd\xc3\x63\xb1\x74\x3c\xc7\xf9\x86\xa4\x21\x42\x08\x8f\xc5\xfa\x53\xc6\xf7\x28\x5b\x7f\xc2\xca\x5d\xde\x21\x95\x2e\x3d\x03\xf8\x8e\x25\x07\x92\x71\xa4\x7e\x7b\x03\x21\x6c\xcd\xa2\xe0\xc5\x67\x46\x20\x84\x00\x36\x06\x61\x56\x19\x4e\xaa\xd5\x55\x7f\x19\x31\x4d\x6c\x96\x1c\x72\x60\x11\xfc\x11\xf1\xc2\x2d\x1b\x61\xaf\x79\x80\x67\x67\x21\xb1\x3a\x48\x66\x32\x5b\xdf\xa0\x2c\x73\x97\x28\xe2\xc5\xd2\x87\xd2\x5b\x7f\xd4\x30\xd7\x73\x7e\x58\x32\xe9\x2c\x9d\x74\x44\xa8\xa0\xec\x7a\x9d\x82\xb2\x71\x99\x74\x96\xce\x58\x3e\x0a\xcd\xc8\x47\x21\x36\x2e\x93\xce\xd2\xb9\x90\xcf\xce\x3d\xfa\x30\x47\x6b\xb7\xf4\xe1\x38\x28\x77\xb3\x97\xea\xea\xb4\xa2\xbd\x54\xc3\x59\xdd\x70\x71\x2c\x68\xc2\xdc\xd2\x87\xe3\xa0\x1a\x25\xe0\x96\xf0\x0e\xb6\xf0\xf2\x02\xc1\xa6\x84\x30\xac\xeb\xd4\x83\x9f\x42\x70\x8f\xdd\xbb\xa3\xfd\xee\xd9\x59\x34\x99\xac\x4a\x67\xf1\xa3\xcd\xab\xb4\x82\x5f\x5f\xc1\xa3\x05\x7c\x33\xa7\x7e\xc7\xcb\xf7\x4f\x9a\x8f\x9f\x41\x2d\x14\xd7\x98\x8e\xf5\x41\x92\xcb\xb1\xb1\x06\xf4\xf0\xd1\x24\x21\xea\x31\x4a\x71\xf5\xee\x70\x29\x86\x37\xf7\xa1\x14\xbb\xab\x55\x04\x7f\x5a\xfa\xb0\x1b\x13\xca\x83\x89\x0d\x54\x90\x8e\xf3\x51\xe2\x52\xb8\xa4\xe3\xb8\x44\xa2\xa8\xf9\xe9\x6b\x02\x50\xa6\x3c\x4b\x83\x54\x94\x4e\xe3\xaf\xa3\xe0\xca\x15\x3e\xfc\x77\x29\x74\xda\xa2\x3a\xe6\x67\x46\x5c\x5d\xe0\x55\x08\x8b\x53\x3c\x51\x15\xa5\xfa\x29\x42\x05\x06\x83\x79\x1f\xc2\xf6\x6d\x57\xb7\xd5\x90\x76\x16\x31\x26\xe8\x90\x29\xeb\x4d\x55\xe4\xba\xaa\xdb\x38\x1a\xda\xed\xd2\x87\x2e\xe8\x9e\xf3\xac\xee\x24\xa2\x3b\xa4\x92\xb5\x1b\xa4\x0d\x6e\xfa\xa4\xc1\x55\x41\xce\x70\xef\x1a\x5c\xbd\x48\x50\x56\x60\x2b\x8f\x2f\xe8\x4b\xef\xb4\x69\x61\x32\xe8\x9d\xaf\xee\x5c\xd2\x72\x6e\x63\x73\xdc\xc3\xb7\xd2\x1f\x05\x06\x14\xea\x8f\x91\x95\x96\x5e\x6c\x32\xd7\xbc\xf7\x21\x04\xdb\xdd\x9b\x53\x08\xfc\x32\x58\x66\xc1\x76\xf7\x6b\x5b\x69\x23\x18\x5c\x8a\x55\x0f\x67\x87\x7b\xa7\xbf\x97\xd7\xc7\x5b\x5d\x19\xf0\xf5\x79\xc0\x69\x71\x5c\x8a\xf3\xae\xb9\xe5\xc9\x48\xcf\x50\x02\xa5\xbe\x8b\x12\x9e\x61\xb3\x81\x27\x2e\xbf\x22\xc9\x0f\x2c\x06\xc2\x25\x70\xa1\x68\x4e\xbf\x63\x09\xfb\x43\x02\x94\xc1\xbf\xbf\xfb\x20\x71\xce\xbf\x61\x40\x0a\x0a\x9e\x63\x10\x9c\x32\x65\x15\x26\x62\x76\xa6\x56\x8a\x19\x4f\x86\x87\xc3\x2d\x4f\x82\xed\xe5\x9e\xce\x2a\x48\x9f\x23\xa6\x39\xe2\x84\xb3\x9b\xa4\xec\x6c\xc6\x1d\x2a\x27\x67\x72\x5e\x63\x2c\x16\x65\xd3\x2c\xca\x4e\x59\x3c\x9e\x64\x75\x1e\xaf\x3a\xd2\x9f\x73\x1e\xeb\x33\xd5\x42\x67\xc7\x7a\xc7\x63\xd2\x9f\x7a\x4d\x6b\xb5\x4b\xe7\x33\xe1\xe5\x65\xac\xf5\x89\xdf\xde\x2d\x25\x10\x6c\xc6\x61\x66\x2c\x2d\x4c\x8d\xbe\x0d\xcd\xbe\x88\x0f\x81\x67\x35\xff\xca\x54\xb0\x6f\xfe\xb6\xf9\xea\xb1\x31\xb4\x69\x1d\xb5\xc1\xfc\xcd\x9f\x2e\x1a\x05\x63\x0e\x02\xbd\x0b\xd7\x3c\xae\x02\x78\xf5\x4a\x5b\x84\xde\x0e\x6d\x9b\xd0\xf3\x09\xc1\x48\xe9\x56\xdd\x35\x7c\xcc\xff\xe0\x1c\x51\x16\x63\x39\x79\x7b\xb2\x87\xec\x14\xee\x69\xc2\xf6\xb4\x67\xa6\x9a\x89\x5d\x33\x87\xed\x8f\x25\x70\xbd\xd1\x1c\xf5\xbd\xf7\x73\x6c\xef\xb8\xeb\xbd\xa7\xec\xe4\xff\x02\xb7\xa0\xcc\x87\x88\x17\xbd\xba\xab\x35\x4d\xea\x9e\x5f\x39\xb1\x4e\xe5\x61\x86\x75\x1e\x75\xce\x0f\x73\x8c\xf3\xb8\x6f\x7e\x90\x07\x16\x5d\x1a\x9f\xbd\xda\xb2\xee\xa7\xfa\x69\x86\xeb\xe9\xd5\xd9\x45\xd7\xf3\xa6\xb5\xb6\x4b\x99\x72\x4b\x4f\x27\xf1\xff\x00\x68\xac\xc8\xce\x2e\x0e\x00\x00"),
 		},
 		"/src/math/math_test.go": &vfsgen۰CompressedFileInfo{
 			name:             "math_test.go",
@@ -437,10 +437,10 @@ var FS = func() http.FileSystem {
 		},
 		"/src/sync/sync.go": &vfsgen۰CompressedFileInfo{
 			name:             "sync.go",
-			modTime:          mustUnmarshalTextTime("2017-07-25T19:26:36Z"),
-			uncompressedSize: 1173,
+			modTime:          mustUnmarshalTextTime("2026-10-14T09:45:07Z"),
+			uncompressedSize: 1583,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x92\x41\x4f\xdb\x40\x10\x85\xcf\xde\x5f\xf1\x94\x4b\xed\x90\x38\xd0\x56\x3d\x20\x72\x02\x09\x21\xb5\x70\x80\xaa\x87\x28\xaa\x36\xf6\x38\x9e\x64\xbd\xeb\xee\xac\x49\x29\xe2\xbf\x57\x9b\x04\x12\x20\xe4\xe4\xec\xce\x7c\x6f\x76\xde\x1b\x8d\x70\x34\xeb\xd8\x94\x58\x88\x52\xad\x2e\x96\x7a\x4e\x90\x07\x5b\x28\xc5\x4d\xeb\x7c\x40\x6f\xce\xa1\xee\x66\x79\xe1\x9a\xd1\xdc\xb5\x35\xf9\x85\xec\x3e\x16\xd2\x53\xea\x5e\x7b\x08\x35\xbf\x34\x07\xf2\x82\x31\x1a\xbd\xa4\xb4\xd1\xed\xa4\xdf\xb1\x0d\x5f\x3e\x4f\x27\xd3\xa2\xd6\x16\x33\xe7\x4c\xa6\x54\xd5\xd9\x02\xbe\xb3\x81\x1b\xfa\x7d\x4b\x8d\x2e\xfe\x74\xec\x29\x15\x6c\xeb\x33\x3c\xaa\x84\x2b\xf4\x05\xe3\x31\x8e\xe3\xbf\xa4\xa8\x71\xba\x25\xef\xb1\x92\x64\x27\x3c\x91\x29\xc6\xd0\x6d\x4b\xb6\x4c\x5f\x1d\x0f\x50\xd4\xb1\xf6\x6c\x58\xd4\x2a\x79\x52\x49\x5f\x86\x43\xf5\xa4\xd4\x68\x84\x9d\xfe\x8f\x2e\xd0\x5f\xb0\xc0\xf0\x92\xf6\xce\x07\x98\x75\x01\x95\xf3\x68\xbd\xab\xd8\xb0\x9d\xa3\x70\x36\x90\x2d\xa9\xc4\xba\x8b\x24\x8f\xac\x0d\x61\x57\xc5\x02\xeb\x02\xa4\x6b\xe3\x2a\xa9\x1c\x40\x1c\x16\x9d\x04\x74\x42\x08\x35\x41\x74\x43\xe0\xa6\x35\xd4\x90\x0d\x3a\xb0\xb3\xd0\x72\x60\x39\x6b\xfe\xdd\xcd\xc5\xcd\x29\xae\xec\x3d\x49\xe0\xb9\x0e\x91\xc1\x92\xe3\xaa\x02\x87\x4f\x82\xd6\x89\xf0\xcc\x10\x82\xdb\x41\x07\x71\x58\xe1\x92\x3c\x4a\x17\xa7\x12\x37\x80\x0b\x35\xf9\x15\x0b\xc1\x53\xe3\xee\x37\x20\x14\xae\x89\x1d\xf9\x47\x0e\xad\xdf\xb7\xb3\x69\x00\xc3\x95\xdb\x38\x11\x3d\x7a\x99\xf0\xa7\xd0\xe6\x8a\x2b\x58\xa2\x92\xca\xd1\xf3\x68\xb9\x4a\x0e\x39\x9f\x45\x37\xde\xaa\x7a\x32\xa4\x85\xf6\x05\x6b\x6d\x4b\x57\x55\x1f\x68\x3e\xdf\x1e\x94\xed\xcb\xd1\x91\x52\xc9\x2a\xc6\xe8\x55\x3a\xd6\x51\x33\x64\xd3\x55\xb6\x8b\x9b\xa7\xd0\x79\x1b\xc3\xa2\xb6\xd1\x5b\x4d\x8e\xa7\xb1\x3d\x7e\x9d\x9c\x4e\xd5\xbb\xe4\xad\x0e\x82\x4a\x32\x14\x68\x2f\x8f\x03\x48\xf6\xc2\x3d\x1b\x22\xf8\x8e\xde\xbd\xde\xba\xc0\xd5\xc3\x77\x96\x70\x5e\x53\xb1\x4c\x85\xff\x11\xe2\x12\xda\xe0\x33\x3c\xbe\x2d\x2f\xb4\xbd\x6d\xd9\xa6\x0c\xb6\x21\x5b\x6f\x27\x8a\x6f\x1e\x81\x4a\x1b\xa1\x6d\xdc\xcf\x5d\xfb\x00\x57\x21\xb6\xe5\xdb\xf6\x6b\x6d\xdd\x1b\xcf\xad\x8e\x13\x34\x94\x66\x91\xf8\xed\x6b\xa4\xc5\x18\x05\x34\x6c\x0c\x0b\x15\xce\x96\x18\xe3\xe4\x78\xfd\x7b\x91\x5a\x48\x7e\x69\xdc\x4c\x9b\xfc\x92\x42\xda\xbb\xd0\x81\x7a\x59\x7e\x4d\xab\x34\xcb\xcf\xb5\x31\x69\x6f\x4e\xe1\x8e\x9b\x78\x7a\x15\xc1\x69\x86\xfe\x3e\x53\x3d\xa9\xff\x01\x00\x00\xff\xff\x87\x12\xfc\xbc\x95\x04\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x54\xc1\x6e\xdb\x46\x10\x3d\x73\xbf\x62\xaa\x43\x4a\xca\x32\x65\xb7\x45\x0f\x6e\x74\x72\xd0\xc0\x40\x9b\x1c\x92\xa0\x07\x43\x28\x56\xcb\xa1\x38\xd2\x72\x97\xdd\x19\x5a\x71\x0d\xff\x7b\xb0\x24\x25\xca\xb2\xac\x13\xc9\x99\x79\xf3\xde\xbc\x07\xcd\xe7\x70\xb1\x6a\xc9\x16\xb0\x61\xa5\x1a\x6d\xb6\x7a\x8d\xc0\x8f\xce\x28\x45\x75\xe3\x83\xc0\x64\x4d\x52\xb5\xab\xdc\xf8\x7a\xbe\xf6\x4d\x85\x61\xc3\xe3\xc3\x86\x27\x4a\x3d\xe8\x00\x8c\xf5\x3f\x9a\x04\x03\xc3\x02\x6a\xbd\xc5\xb4\xd6\xcd\xfd\xb4\x25\x27\xbf\xfe\xb2\xbc\x5f\x9a\x4a\x3b\x58\x79\x6f\x33\xa5\xca\xd6\x19\x08\xad\x13\xaa\xf1\xdf\x2f\x58\x6b\xf3\x5f\x4b\x01\x53\x86\xa1\x3f\x83\x27\x95\x50\x09\x53\x86\xc5\x02\xae\xe2\x5b\x62\x2a\xb8\x19\x90\x8f\xb0\x92\x64\x5c\x7c\xcf\x4b\x58\x80\x6e\x1a\x74\x45\xfa\xe2\xf3\x0c\x4c\x15\x7b\xdf\x5f\x9a\x4a\x25\xcf\x2a\x99\xf2\xe5\xa5\x7a\x56\x6a\x3e\x87\x71\xff\xdf\xad\xe0\x77\x20\x06\x4b\x5b\x3c\xfa\x3e\x83\x55\x2b\x50\xfa\x00\x4d\xf0\x25\x59\x72\x6b\x30\xde\x09\xba\x02\x0b\xe8\xa6\x90\xf3\x88\xd5\x3d\x1f\x75\x11\x83\xf3\x02\xdc\x36\xf1\x94\x58\xcc\x80\x3d\x6c\x5a\x16\x68\x19\x41\x2a\x04\xd6\x35\x02\xd5\x8d\xc5\x1a\x9d\x68\x21\xef\x40\xf3\x99\xe3\x74\xf8\x5f\x3f\x7f\xf8\x7c\x03\x77\xee\x01\x59\x68\xad\x25\x62\x10\xe7\x70\x57\x02\xc9\xcf\x0c\x8d\x67\xa6\x95\x45\x10\x3f\x82\xce\x22\x59\xa6\x02\x03\x14\x3e\xb2\x62\x3f\x03\x2f\x15\x86\x1d\x31\x42\xc0\xda\x3f\xf4\x40\x60\x7c\x1d\x27\xf2\xb7\x1c\xea\xf4\x8d\x36\xcd\xc0\x52\xe9\x7b\x27\xa2\x47\x07\x86\xdf\x18\xfb\x12\x95\xe0\x10\x0b\x2c\xe6\x7b\x6a\xb9\x4a\xce\x39\x9f\x45\x37\x4e\xb7\x06\xb4\xa8\x19\x8f\x17\x56\xda\x15\xbe\x2c\xdf\xd8\xb9\xaf\x9e\x5d\x3b\xe5\x8b\x0b\xa5\x92\x5d\x8c\xd1\x8b\x74\x74\x51\xb3\xe8\xd2\x5d\x36\xc6\x2d\xa0\xb4\xc1\xc5\xb0\xa8\x21\x7a\xbb\xfb\xab\x65\x1c\x8f\x4f\xd7\x37\x4b\xf5\x2a\x79\xbb\xb3\x40\x05\x5a\x14\x3c\xca\xe3\x0c\x38\x3b\xe0\xbe\xbf\x04\x09\x2d\xbe\x52\xef\xbc\x50\xf9\xf8\x17\xb1\xdc\x56\x68\xb6\x29\xd3\xff\x08\xf1\x08\x8d\x84\x0c\x9e\x4e\xdb\x8d\x76\x5f\x1a\x72\x29\x01\x39\xc9\xba\xeb\xc4\xe5\xbd\x08\x28\xb5\x65\x1c\xe2\x7e\xeb\x9b\x47\xf0\x25\xc4\xb1\x7c\x18\xff\xa4\x9d\x3f\xf1\xdc\xe9\xc8\xa0\xc6\x34\x8b\x88\xbf\xff\x16\xd1\x62\x8c\x04\x18\x8d\x77\x05\x2c\xe0\xfa\x6a\xff\xdb\x97\x6a\xb2\x96\x4e\xeb\xdd\x51\x9a\xe0\x0d\x32\xc7\x33\x6e\x38\xff\x68\xfd\x4a\xdb\xfc\x23\x4a\x3a\x19\x2a\x93\xec\x8f\x43\xd3\x4f\x5d\xd3\x37\x57\x60\x49\x0e\x0b\x78\xf7\x6e\x5f\xea\x47\xaa\x10\x99\x4d\xb2\x57\x8d\xf1\xdc\x12\x77\xec\xdb\x6f\xb5\xb5\x63\xff\xc1\x55\x90\xfc\xce\x15\xf8\x3d\xbd\xca\xf2\xbb\xa8\x2e\xcd\xa6\x03\xed\x8b\x43\xed\xfa\x50\xeb\xfe\x31\xa2\x08\x0c\xa5\x0f\xb5\x76\x06\xcf\x09\x19\xab\x9d\x98\xf1\xf5\xac\xa0\xb1\xdc\x8f\x3b\xbf\x7b\x43\xd1\xc0\xb9\xb3\x21\x3d\x9e\xeb\xd5\x75\x83\xf9\x9f\xd6\x6b\x49\x33\x98\x1e\x9b\xd0\x33\x1f\xe6\x4f\xe8\x7e\xd0\x82\x93\x2c\xff\x84\xbb\x34\x1b\x90\xd6\x28\x5f\xbb\x43\xed\x85\xbf\x44\x53\xcf\xea\xc7\x00\x66\x73\xa4\xfa\x2f\x06\x00\x00"),
 		},
 		"/src/sync/sync_test.go": &vfsgen۰CompressedFileInfo{
 			name:             "sync_test.go",
@@ -469,10 +469,10 @@ var FS = func() http.FileSystem {
 		},
 		"/src/syscall/syscall_unix.go": &vfsgen۰CompressedFileInfo{
 			name:             "syscall_unix.go",
-			modTime:          mustUnmarshalTextTime("2016-06-27T01:43:45Z"),
-			uncompressedSize: 2931,
+			modTime:          mustUnmarshalTextTime("2026-10-14T11:17:40Z"),
+			uncompressedSize: 6225,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x56\xdd\x6e\x1b\x37\x13\xbd\x5e\x3e\xc5\x78\xf1\x21\xe0\x46\xfc\x56\x3f\x6d\x8d\xa2\xae\x2e\x5c\x43\x35\x04\xb8\x71\x10\xc9\x4d\x8a\x20\x30\x28\xed\xac\x4c\x69\x45\xaa\x24\x57\x8e\x90\xe8\xdd\x0b\xfe\xac\x25\x4b\xba\x49\x0d\xf4\x26\x77\x8b\x9d\x33\x33\x87\x87\x87\x43\xb6\xdb\xd0\x9a\xd4\xa2\x2a\x60\x6e\xd8\xd9\xa3\x90\x85\x7a\x34\x84\xac\xf8\x74\xc1\x67\x08\x66\x63\xa6\xbc\xaa\x08\x11\xcb\x95\xd2\x16\x28\x49\x52\x5d\x4b\x2b\x96\x98\x92\x24\xad\xa5\xe1\x25\xa6\x84\x24\xe9\x4c\xd8\x87\x7a\x92\x4f\xd5\xb2\x3d\x53\xab\x07\xd4\x73\xb3\xfb\x98\x9b\x94\x64\x84\x94\xb5\x9c\x42\x4c\xbf\x47\xb9\x36\x34\x83\x8f\x9f\x8c\xd5\x42\xce\xe0\x0b\x49\x56\x5a\x4d\xd1\x18\xf8\xa5\x0f\x73\x93\x5f\x57\x6a\xc2\xab\xfc\x1a\x2d\x4d\x63\x24\xcd\x48\x22\x4a\x68\x70\x7d\x8f\xbb\x93\x05\x96\x42\x62\xe1\x4a\x24\x1a\x6d\xad\x25\x48\x51\x91\x64\x4b\x92\xb9\x19\xc8\xb5\x2b\x18\x73\x42\x39\x94\x6b\x57\x0a\xe5\x7a\x81\x9b\x53\xfd\x6e\x27\x73\x9c\xda\x34\xcb\xaf\x78\x55\xd1\xd4\xa1\x52\x06\xbe\x58\xc8\xf3\x49\x4b\xbe\x40\xda\x2c\x80\x41\x2c\x97\xdf\xa0\x9c\xd9\x07\x9a\x65\x24\x29\x95\x06\xe1\xa0\x9d\x0b\x10\xf0\xeb\x11\xe4\x02\x44\xab\xe5\x79\x2f\x70\xe3\x70\x0d\x60\x28\x0b\xfc\x4c\x45\x96\x8f\x7c\x71\x9a\x91\xc4\xb7\xfd\x28\x3e\x41\x1f\x1c\xb8\x05\x69\x3f\x85\x56\x20\xe5\x59\x2f\x70\xb3\x8f\xdf\x92\x46\x0c\x97\x48\xb6\x51\x7f\x83\x16\xe5\xfa\x7e\x4a\x17\x0c\xd6\x10\xb8\x67\xff\x46\xfd\xb3\x13\xea\x1f\xab\x9c\x8f\x1c\x33\x06\x6b\xcf\x68\x4b\xc8\x9a\xeb\xc6\x56\x7f\xa8\xa2\xae\x10\x5e\xcf\x4d\x1e\x04\xf7\x41\x5e\x69\xe4\xc5\x66\xac\x05\x16\x63\x75\xa3\x78\x01\x7d\x28\x79\x65\xd0\x87\x97\x42\xd6\xe6\x56\x22\xf4\xe1\xff\xdd\x66\x4d\xa1\x1e\x95\x7c\x89\x4f\x4b\xda\x95\x75\xd4\x0a\x2c\x51\x83\x43\xd3\x2c\x1a\x65\xaa\xd6\xa8\xbd\xb2\xed\x36\xec\x7c\x03\xa2\x84\x18\xc4\x82\x24\x5b\x1a\x96\xfd\x9c\x73\xbf\xef\xa1\xae\x90\x28\x4f\x51\x76\x91\x67\x66\x74\xfb\x91\x9c\x5c\x9b\xd5\x35\x7a\x42\x7f\xd7\x42\xe3\x09\xfd\x63\xc4\xe9\x9f\x78\x72\x01\x78\xca\xfe\xc9\x8a\x4b\x31\xa5\xa9\xc7\xba\x8e\x07\xb4\x9b\xe4\x7c\x28\xd7\x6a\x81\x34\x8d\xf1\xf4\x99\x61\x9e\x25\x79\x0e\x4e\xd9\xec\xc9\x43\xa3\xa8\xb7\xd5\x7c\xc5\x80\x77\x19\xf0\x1e\x03\xfe\x03\xd4\x42\xda\x95\xd5\x19\x50\xdd\x65\xa0\x7b\xcd\x0f\x06\xa8\x35\x0c\xb4\x96\xca\xab\x2f\x4a\x28\xdd\x42\x9b\x8d\x4b\x47\x0d\x8d\x0b\x28\x9d\xb3\x1a\x71\xb5\x43\x95\x0d\xdb\xc3\x7e\xd9\xee\xc0\xc7\x46\x54\xc7\xa3\xd3\xc9\xf2\xa1\xb4\x34\xcb\xd8\x51\xa8\xbb\x0b\x79\x46\x4f\x81\x5e\x13\xf0\x5a\x88\x12\x5c\x3f\x27\xf3\xe8\xaf\xd1\xfd\xfb\x77\xc3\xf1\x00\x5e\xbd\x02\xca\xbb\xee\x5f\x17\xbe\x7e\x85\xf0\xd9\x0b\x8e\xe2\x5a\xf3\x4d\xdc\xbe\xa1\xb4\xa8\x25\xaf\x82\x01\x29\xef\x39\xaa\xa6\x12\x53\xdc\x1b\x1c\x93\x8d\x45\x06\x3e\x6d\x7f\x68\x24\xc7\xf9\x3e\x33\x9c\xa5\xf4\x7f\x3e\x21\x8d\x89\x99\x3f\x75\x42\xda\xb1\xba\x52\xd2\xa8\x0a\x23\xf8\x58\x9a\x83\x46\x0c\x3a\x0c\x3a\xa7\x96\x3a\xf8\x30\x1c\x07\xf5\xc3\xac\xce\xaf\x15\x7e\x16\x36\x0e\x15\xdf\xed\x3d\xd7\x32\xce\x99\x83\x2e\xcd\xf9\x0c\xf5\x07\x97\x57\x57\x83\xd1\xa1\x71\xce\x8f\x76\x92\x01\xff\x91\x01\xff\x89\x01\x3f\x7f\xb1\x8b\xce\xbf\xd1\x46\xfb\xcd\xff\x13\x4b\x9d\xf5\xa1\xd7\xe9\xc1\x17\x68\xb7\x61\x81\x5a\xe6\xca\x68\xac\x90\x1b\x04\x25\xe1\x76\x04\x1f\x18\x3c\xf0\xd5\x0a\xa5\x01\x21\x41\x48\x61\x41\x95\x90\x2a\x93\x42\xbc\x9a\x9b\x6d\xdf\xdb\x88\xed\xb7\xed\xc5\x3b\xfe\xf8\x5d\x9c\xe3\x97\xf8\x75\xa7\xd1\xf7\x6a\xd9\x97\xa8\xf7\xdb\xc6\xe2\x5b\xab\x7f\xd7\x6a\x19\x9f\x25\xe6\xe9\x76\xa6\xaf\xc3\xf4\x43\xad\x95\xf6\xd2\xec\x8f\xcf\xfd\xdb\xef\x4e\x48\xfb\xf3\xa5\x9f\x79\x59\xfe\x06\x1f\x69\x85\x92\x9a\x0c\x5a\xd0\x6d\x5e\x58\x0c\x26\x2e\x51\x73\x39\x43\x08\x73\xd5\x21\xe2\xed\x3c\x71\x73\xad\x73\x78\x23\x33\x18\x0c\xdf\xfc\x79\x79\xd3\xdc\xcc\x7e\x38\x8e\xd0\xc6\x97\x17\x83\x49\x10\xe0\x20\x10\x9a\x33\xe8\xec\xb4\x08\x4b\xc9\x68\x78\x0d\xe7\x6f\x95\x70\xc3\x3b\x8e\xdb\x3b\xff\x93\x66\x4e\x67\xf7\x0e\xd8\x92\x7f\x02\x00\x00\xff\xff\xba\x7d\x55\xf6\x73\x0b\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x58\x5f\x73\xdb\xb8\x11\x7f\x16\x3f\xc5\x9a\x73\x73\x25\xcf\x3c\xca\xf6\xb5\x99\xce\xb9\x7a\xf0\xa5\x4a\x46\x9d\x34\xf6\x44\x4e\xef\x3a\x37\x37\x19\x88\x5c\x48\xb0\x28\x80\x05\x40\x39\x9a\x44\xdf\xbd\xb3\x00\x28\x52\x7f\xea\xa6\x49\xe7\x1e\x7a\x7d\xb1\x25\x61\x77\xb1\xfb\xc3\xee\x6f\xb1\x18\x0e\xe1\x7c\xd6\x88\xaa\x84\x07\x93\x9d\x3d\x0a\x59\xaa\x47\x13\x45\x35\x2b\x96\x6c\x8e\x60\x36\xa6\x60\x55\x15\x45\x62\x55\x2b\x6d\x21\x89\x06\xb1\x6e\xa4\x15\x2b\x8c\xa3\x41\xdc\x48\xc3\x38\xc6\x51\x34\x88\xe7\xc2\x2e\x9a\x59\x5e\xa8\xd5\x70\xae\xea\x05\xea\x07\xd3\x7d\x78\x30\x71\x94\x46\x11\x6f\x64\x01\x41\xfd\x1d\xca\xb5\x49\x52\xf8\xf9\x17\x63\xb5\x90\x73\xf8\x10\x0d\x6a\xad\x0a\x34\x06\xbe\x1f\xc1\x83\xc9\x5f\x56\x6a\xc6\xaa\xfc\x25\xda\x24\x0e\x2b\x71\x1a\x0d\x04\x87\x56\x6e\xe4\xe4\xde\xca\x12\xb9\x90\x58\x92\x89\x81\x46\xdb\x68\x09\x52\x54\xd1\x60\x1b\x0d\x1e\xcc\x58\xae\xc9\x60\xd0\xf1\xe6\x50\xae\xc9\x14\xca\xf5\x12\x37\xa7\xf6\xbb\x9d\x3d\x60\x61\xe3\x34\x7f\xce\xaa\x2a\x89\x49\x2a\xce\xc0\x19\xf3\x7a\x4e\x69\xc5\x96\x98\xb4\x01\x64\x10\xcc\xe5\xaf\x50\xce\xed\x22\x49\xd3\x68\xc0\x95\x06\x41\xa2\x17\xd7\x20\xe0\x4f\x47\x22\xd7\x20\xce\xcf\x9d\xdf\x4b\xdc\x90\x5c\x2b\x30\x91\x25\xbe\x4f\x44\x9a\x4f\x9d\xf1\x24\x8d\x06\x6e\xdb\x9f\xc5\x2f\x30\x02\x12\x3e\x87\x78\x14\xc3\xb9\x77\xca\x79\xbd\xc4\x4d\x5f\x7e\x1b\xb5\x60\x90\x62\xb4\x0d\xf8\x1b\xb4\x28\xd7\xef\x8a\x64\x99\xc1\x1a\xbc\xef\xe9\xe7\xa0\x7f\x76\x02\xfd\x63\x94\xf3\x29\x79\x96\xc1\xda\x79\xb4\x8d\xa2\x35\xd3\x6d\x5a\xfd\x55\x95\x4d\x85\xf0\xcd\x83\xc9\x3d\xe0\x6e\x91\x55\x1a\x59\xb9\xb9\xd7\x02\xcb\x7b\xf5\x4a\xb1\x12\x46\xc0\x59\x65\xd0\x2d\xaf\x84\x6c\xcc\xad\x44\x18\xc1\xb7\x97\x6d\x4c\xde\x5e\x22\xd9\x0a\x77\x21\x75\x66\xc9\xb5\x12\x39\x6a\x20\xe9\x24\x0d\x89\x52\xa8\x35\x6a\x87\xec\x70\x08\x5d\xde\x80\xe0\x10\x16\xb1\x8c\x06\xdb\xc4\x87\xbd\xef\xf3\x68\xe4\x44\xc9\x90\xe0\xa7\x5c\xa6\x95\xbd\x64\xa4\xf3\x18\x9c\x8c\xcd\xea\x06\x9d\x43\xff\x68\x84\xc6\x13\xf8\x87\x95\x38\xf5\xbb\xb5\x82\xa7\xd2\x7f\x50\x33\x29\x8a\x24\x8e\xd3\xb0\xe3\x81\xdb\xad\x72\x3e\x91\x6b\xb5\xc4\x24\x0e\xeb\xf1\x5e\xc2\xec\x29\x39\x1f\x08\xd9\x74\x97\x43\xd3\x80\xb7\xd5\xac\xce\x80\x5d\x66\xc0\xae\x32\x60\xdf\x41\x23\xa4\xad\xad\x4e\x21\xd1\x97\x19\xe8\xab\xf6\x87\x0c\x50\x6b\x18\x6b\x2d\x95\x43\x5f\x70\xe0\x14\x68\x7b\x70\xf1\xb4\x75\xe3\x1a\x38\x9c\x75\xe0\x6a\x92\xe2\xad\xb7\x87\xfb\xa5\x5d\xc1\x87\x8d\x12\x1d\x4a\xe7\x22\xcd\x27\xd2\x26\x69\x9a\x1d\x2d\x5d\x76\x4b\xce\xa3\xdd\xc2\x55\xbb\xe0\xb0\x10\x1c\x68\x3f\x82\x79\xfa\xf7\xe9\xbb\x1f\xdf\x4c\xee\xc7\xf0\xf5\xd7\x90\xb0\x4b\xfa\xed\x12\x3e\x7e\x04\xff\xf1\x2a\x6d\x13\x41\xba\x40\x33\x50\x4b\xf2\xfb\x51\x0b\x8b\x53\x5b\x26\x42\xda\x84\x5d\xa6\xde\x6d\xf7\xe5\xbb\x34\xbd\x26\xa9\x7e\x9a\xb4\x7e\xca\x34\x83\x0b\x67\xa8\xcd\x1a\xad\xd9\x26\xe4\xc5\x44\x5a\xd4\x92\x55\x3e\xb3\x13\x76\x45\x18\x98\x4a\x14\xd8\x63\xa4\xd9\xc6\x62\x06\x4e\xad\xcf\x46\x83\x63\x7d\xa7\xe9\x8b\x34\xfe\xca\x29\xc4\x41\x31\x75\xe5\x2c\xa4\xbd\x57\xcf\x95\x34\xaa\xc2\x20\x7c\x8c\xf9\xc1\x46\xce\xfb\x8b\x53\x18\xbe\x19\xdf\xfc\x99\x20\xf4\xb0\x5d\x9c\x46\x8d\x2a\x64\x6a\x4b\x21\x93\xcf\x42\xeb\x78\xd7\xf1\x4f\x93\x7b\xa7\x1a\x5a\x4f\xfe\x52\xe1\x7b\x61\x03\x47\xba\x18\x7f\x64\x5a\x06\xda\x3c\x30\xdf\xd2\x8d\xdf\x65\x7c\xf3\xfc\xf9\x78\x4a\x75\x30\x1c\x76\x8e\xba\x4f\x06\x9a\x1a\xac\x02\x09\x84\xbe\x01\xae\xd5\x0a\xec\x82\xf8\x88\xc9\x92\xe9\x12\x84\xac\x1b\x0b\x8a\xc3\x6b\x55\x62\xfe\x60\x28\x36\x45\x22\x64\xcc\x9f\x71\x9d\xc1\xa3\xb0\x0b\xd5\x58\xaf\xea\xab\x02\x56\xbe\x10\x61\x62\x03\x4f\x19\x4f\x88\x40\xe0\x29\x0b\xba\x91\xe4\x3e\x28\x49\xa6\x82\xf9\x3c\xf4\xdb\x1d\x9c\x75\x57\x8d\x92\xf6\xa6\x2a\xa5\xff\xbd\xe2\x74\x67\x30\x53\xaa\x72\x19\xfd\x49\x8c\xf4\x6f\x08\x29\xc0\x79\xe1\xe0\xf3\x2c\x4e\xa8\x73\xd7\x67\xa4\x2a\xf1\xc5\x34\x09\xfa\xde\x18\x37\x7d\x72\xfd\x17\xea\x14\x77\x97\x42\x7b\x42\x9e\x4d\xb7\xd1\x53\x55\x53\xa7\xd1\x60\xd6\xf0\x13\x81\xfd\xd0\x70\x8e\x7a\xd7\xf8\xe9\x10\xdb\x82\xf0\x02\xb3\x20\xb0\xff\xe3\xc6\xe2\x2d\xe7\x06\x2d\x2d\xc8\xd0\xf8\xc9\x35\x36\x67\x42\x3a\x0e\xf3\xbe\x0f\x7a\x1d\xe8\xb8\x27\xb9\x6a\x40\x5f\x04\xa1\x3b\x5d\x03\xf6\xf9\x70\x40\x45\x3c\xa6\x62\x11\xe6\x2f\xf4\x81\x84\x31\x4f\xa8\xd9\x8d\xb5\x56\x3a\xf5\x42\x82\xc3\x59\x2b\x11\xf4\x42\x6b\xc0\x20\xb1\xf5\xff\xcc\xa3\xb0\xc5\x02\x9c\x51\x1f\x4c\xa1\x4a\x8c\xbb\x8b\x44\xab\x5e\x30\x83\x10\x8f\x6f\x5f\xc4\xdf\x07\x7b\x1a\x46\x54\xe6\xbd\xc5\x9b\x97\x37\x93\xd7\xbb\x75\x1f\xbb\xef\x6f\x30\x1c\x82\x71\xb5\x22\x0c\x48\x25\xbf\x9d\x55\xaa\x58\xfa\x6b\x53\x3e\xcf\x81\xf0\x32\x6a\x85\x60\x51\xaf\x84\x64\x95\xc9\xbd\x95\x12\x39\x6b\x2a\xbb\xdb\xd3\x27\xeb\x68\x77\x0d\xc8\x60\x3c\xb9\xed\x47\xe4\xfe\xba\xa6\xed\x3d\xe4\x26\x9c\xa5\x2b\x84\x8d\x2c\x62\x97\x26\xb3\x86\xbb\xff\x32\x23\x6c\x03\xf1\x47\xad\x26\xc1\xe7\xdd\xef\x53\x8e\x0e\x3c\x15\x1a\xf6\x36\xdc\x68\x86\xc3\x1d\xd3\xfb\x0f\xe6\x98\x04\x42\x79\x83\x55\xfb\x9c\xa0\x1a\xeb\x48\x41\x93\x65\xa5\x41\xf1\x5e\x05\x67\xc0\x4b\xb8\xa4\xc5\xab\xcf\xe2\x05\x32\xd5\x51\x43\x67\x55\x69\x12\x20\x43\x61\x7b\x61\xa0\x60\xb5\x6d\x34\x96\x6e\x1f\x98\xab\xbb\x3d\xea\x27\x1e\x76\x71\x2e\x44\xb1\x00\x77\xda\xc2\xa9\x51\xbc\x16\x65\x1b\x57\xe1\xc5\x03\xf5\xec\xfa\x1f\x2f\x3d\xcf\xfc\xea\x0c\xf4\xf1\xe3\xa1\xde\x61\x64\x71\x7a\xf2\x22\xfb\x7f\xda\xfa\xdf\xa2\xad\xa7\x99\x69\xbf\x14\x9e\xa2\xa7\x5a\xd4\x68\xf2\x3d\xd3\x77\x93\xbb\x71\xfc\x14\x3f\x91\xc0\x7f\x9b\xcc\x7c\x69\x79\x36\xe3\x65\x8f\xce\xbe\x94\xc9\x7c\x6e\xef\xe8\x84\x90\xe1\x26\x10\x4d\xef\xee\xe2\x48\x64\x37\x2c\xf9\xe2\x2b\x15\x1a\xf9\x3b\x4b\x03\xe2\x5a\x94\xee\x56\x23\xac\xe7\x13\xb3\x91\xc5\x42\x2b\xa9\x1a\x13\x28\x32\x83\x4a\x2c\xd1\xb3\xd9\x42\xac\x0c\xd9\x9e\x35\xb2\xac\x50\x1b\x07\xf7\x4c\xab\x47\x83\xba\xbd\xc6\xec\xd7\x5c\x6f\xb8\x4b\x21\xe1\x66\xef\xfb\xa9\x69\xaf\x9b\xe9\x92\x74\x2f\x31\xa9\x6e\xbb\x01\xcd\x61\xe6\x7e\x3a\x9c\x93\x78\x3b\xfe\x72\x73\x8a\x65\xba\xf2\x77\x5f\x7c\x4a\x76\x87\x94\x7e\x02\x33\xb5\x35\xfc\x29\xcf\x1a\xe1\x2b\x37\xe1\xd4\xc6\xef\x85\x05\xba\xd4\xfa\x13\x0b\x87\xb4\x1b\xd5\xdd\x19\x84\xbe\x63\x1b\x03\x54\x2b\xc0\xb8\x25\x8c\xaa\xc6\x2c\xa8\x41\x84\x7b\x68\xd1\x12\xfe\x53\xfd\x66\x5c\x19\x7c\x5c\xa0\xa6\xbe\x60\xc1\x58\x55\xfb\x7d\x49\x48\xc8\x39\xd9\x99\x2b\xad\x1a\x2b\x28\xa7\x7d\xd3\x40\x59\x7a\xa1\x5a\xab\xb9\x66\x2b\xca\x1c\xdf\x43\xe8\xc7\x15\x65\xe8\x4e\x27\x9c\x39\x45\x95\x38\x5f\x5d\xaf\xf8\xb0\xf7\xfc\xf0\xc4\x33\xc5\xf5\x93\x6f\x14\x9d\x96\x2f\xa5\xaf\x1c\x04\xbb\x76\xd0\x7b\xc5\xf0\xeb\x04\x6b\x9c\x39\xcc\xc2\x90\x7c\x38\x46\x1c\x8c\xc5\xcf\x8e\xe6\xd4\x0c\xd8\xef\x33\x60\x7f\xc8\x80\x3d\xfb\xe2\x19\xf9\xd9\x7f\x38\x24\xf7\x37\xff\x55\x06\xe6\xb3\x11\x5c\x5d\x5c\xc1\x07\x62\xd6\x25\x6a\x99\x2b\xa3\xb1\x42\x62\x4b\x25\xe1\x76\x0a\x3f\x65\xb0\x60\x75\x8d\xd2\x80\x90\x20\xa4\x70\x53\x51\xac\x4c\x0c\xe1\xe1\x31\x1a\x1c\xcd\x65\xdb\x4f\x1e\xcd\xdc\x59\xbc\x61\x8f\xbf\x89\x57\x8a\xcf\x1c\x5f\x0f\x30\xfa\xad\xa6\xec\x97\xa0\xf7\xc3\xc6\xe2\x9d\xd5\x2f\xb4\x5a\x85\x4b\x87\xd9\xbd\x3d\x26\xdf\xf8\x27\x18\x77\xa9\x77\xd0\xf4\xaf\x75\x7d\xd2\x7a\x2b\xa4\xfd\xe3\x0d\x2d\xc6\x69\xfe\x1a\x1f\x93\x0a\x65\x62\x52\x38\x87\xcb\xf6\xfd\x38\x83\x19\x29\x6a\x26\xe7\x08\xfe\x71\x87\x24\x42\x5b\x9b\x75\x17\xca\x5e\x97\xa0\x9b\xc4\xeb\xbf\xdd\xbc\xea\xbf\x20\xd1\x2b\x4f\x78\x57\xce\x60\x96\x76\xb7\xcd\x6e\xc1\x6f\x9e\xc1\x45\x87\x85\x0f\x25\x4d\xfc\x5b\x7f\x7e\xa7\x84\xb4\xd8\xbe\xf9\xbc\x75\x3f\x26\x29\xe1\x4c\xbd\x69\x1b\xfd\x73\x00\x48\x93\x96\xd4\x51\x18\x00\x00"),
 		},
 		"/src/syscall/syscall_windows.go": &vfsgen۰CompressedFileInfo{
 			name:             "syscall_windows.go",
@@ -487,10 +487,10 @@ var FS = func() http.FileSystem {
 		},
 		"/src/testing/example.go": &vfsgen۰CompressedFileInfo{
 			name:             "example.go",
-			modTime:          mustUnmarshalTextTime("2017-02-27T20:49:55Z"),
-			uncompressedSize: 1424,
+			modTime:          mustUnmarshalTextTime("2026-10-14T11:17:50Z"),
+			uncompressedSize: 1894,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x54\x5d\x6b\xf3\x36\x14\xbe\x96\x7e\xc5\xa9\x20\x45\x5a\x5d\x85\xdd\x06\x7c\x51\xb6\x06\x0a\xa5\x2b\xcd\x7a\x57\x18\xaa\x73\xec\x6a\xb5\x25\x23\xc9\x49\xc7\x9a\xff\x3e\x74\xec\x7c\x8e\xf7\xe6\xbd\x09\x91\x2c\x3d\xe7\xf9\x38\x47\xf3\x39\xdc\xbc\x0f\xb6\x5d\xc3\xdf\x91\xf3\xde\x54\x9f\xa6\x41\x48\x18\x93\x75\x0d\xe7\xb6\xeb\x7d\x48\x20\x39\x13\x75\x97\x04\x67\xc2\xc7\xfc\x1b\x53\xb0\xae\xa1\xbf\xc9\x76\x28\xb8\xe2\xbc\x1e\x5c\x05\x61\x70\xf7\x5f\xa6\xeb\x5b\x94\xd8\xc0\x83\x4b\x18\x9c\x69\xa7\x2d\x05\xd2\x7f\xc2\xbb\xf7\xad\x82\x7f\x39\xb3\x35\xfc\x52\x7d\x98\x94\xfe\xc9\x2b\x56\x77\x49\x3f\x07\xeb\x52\x2d\x45\x59\x96\xf0\xf2\xfa\x04\x00\xb3\xf8\xe6\x44\x01\xd8\xe8\x27\xd3\xa1\xe2\x6c\xc7\x39\x9b\xcf\xe1\x37\xd3\xa7\x21\x20\xc4\xb4\xf6\x43\xd2\x9c\x8d\x7f\x60\x51\x82\x8f\x7a\x45\x0b\xce\xb6\x05\x60\x08\x79\x33\x61\xd7\x2f\x6d\x8b\x52\x68\x01\x37\x7b\x3c\xb8\x01\xa1\x27\x08\xa1\x88\x52\x3e\x7f\x55\x82\xb3\xed\x81\xd5\xb2\xcf\xb4\x5a\x27\x47\x64\x0c\x81\x60\x15\x67\xcc\x47\x7d\xff\x65\x93\xfc\x95\x98\xb1\x43\x69\x28\x61\xcb\x33\x29\x13\x88\x53\x76\x49\x3f\xf9\xad\x54\x9c\xf9\x4f\x28\x21\x85\x01\x27\x25\x2d\x1a\x07\x43\x0f\xd6\x81\x81\x35\xd6\x18\x02\xae\xa1\x32\x6d\x0b\xd1\xc3\x16\xa1\x32\x0e\x02\x56\x7e\x83\x01\x6c\x0d\xe9\x03\x01\x47\x47\xa1\x37\xce\x56\x51\x73\x46\xf7\x20\x67\x20\xc9\x5c\xb6\x8e\x89\x84\xd7\x5d\xfa\x7d\x08\x26\x59\xef\xe4\x91\x85\x5e\x0d\xef\x92\xd8\x29\xc5\x39\x1b\x79\xf8\x88\x50\xdb\x16\x0b\x08\x18\x93\x3f\xb8\x5b\x40\x83\x09\xfc\x90\x7a\x72\x9a\x6d\x35\x9d\x95\x93\x01\x07\xc5\x71\x72\x9d\xd1\x9d\x80\x66\x9d\x1d\xbf\x1f\x03\xd8\x2f\xe5\x96\x9c\x97\x2a\xdf\xfe\x0b\x28\xae\x17\xec\xfc\xe6\xfc\x8b\xad\xcf\x00\x4e\x12\x39\x89\xa4\x3e\x4d\x44\x4c\x5d\xbb\xa0\x8b\xd6\x35\x13\x1f\x92\xb4\x80\xd9\x86\x1a\xe9\x04\x34\x97\x39\x0b\x90\x7a\x8b\x6d\x4c\x80\xda\xd8\x16\xc6\x26\xe7\x8c\xe1\x5e\x01\x45\x40\xb2\x1b\x4f\xb1\x4e\x73\xa0\xff\x0c\xb6\x5b\xf5\xa6\x42\xe9\x87\x94\xbf\x6f\x8d\xfb\xc1\x01\x6c\xf4\x1f\xe4\xe4\xa4\x12\x1b\xfd\xea\x7c\x58\x63\x0e\x9d\xf4\xd9\x1a\xa2\x0f\xe9\xd1\x3a\x8c\xb2\xf1\x49\x65\xf5\xc7\x9d\x0c\xad\xe0\xfa\x9a\x3a\xb5\x3c\xf1\x85\x11\x6b\x4a\x5c\xaf\x26\x7f\x44\xe3\xd3\xe2\xcd\xe5\x29\x22\x4a\x72\xd8\xd7\x52\xd3\xb6\x28\x80\xe2\x3a\xe3\x95\x7b\x99\xed\x00\xdb\x88\x07\x4e\x59\xf2\x55\x09\x04\xf3\x73\xd5\x8f\x15\x1b\x9f\x0a\x42\x3a\x16\x1b\xdd\x20\x90\xab\x12\x84\x80\xef\xef\xcb\x59\x3c\x7b\x22\x6e\x6f\x6f\x61\x79\xf7\xf0\xb8\x80\x59\x04\x39\x8b\x2a\x83\x1f\x5f\x8a\x02\xf2\x00\x14\x04\x38\x06\x9d\xa7\xae\x36\x6d\xc4\xa3\xb4\x8b\x17\xe8\x7f\xf8\xcf\x77\xab\xd5\x09\xfe\x25\xba\x3a\xf2\xbe\x64\x4a\x73\x29\xa7\x47\x62\xc7\xd9\x4e\xaa\x71\xda\x5f\x06\xb7\x1f\x5e\xcd\x19\x36\x7a\x99\xfb\x29\x60\x1a\x82\xe3\x3b\xfe\x5f\x00\x00\x00\xff\xff\x6d\xa8\x39\x72\x90\x05\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x55\x5d\x6f\xdb\x36\x14\x7d\x26\x7f\xc5\xad\x80\x14\xd4\xa2\x30\xd8\xab\x01\x3d\x14\x5d\x52\x14\x28\xd2\x20\x6e\x9f\xd6\x61\xa0\xe4\x2b\x99\x8e\x44\x0a\xe4\x95\xd5\xa0\xf5\x7f\x1f\x48\xca\x91\xed\x6d\xc0\xb0\x17\x43\xfc\xb8\x87\x87\xe7\x1c\x5e\xdf\xde\xc2\x75\x35\xea\x6e\x03\x3b\xcf\xf9\xa0\xea\x67\xd5\x22\x10\x7a\xd2\xa6\xe5\x5c\xf7\x83\x75\x04\x82\xb3\xac\xe9\x29\xe3\x2c\xb3\x3e\xfc\x7a\x72\xda\xb4\xf1\x93\x74\x8f\x19\xe7\x2c\x6b\x35\x6d\xc7\x4a\xd6\xb6\xbf\x6d\xed\xb0\x45\xb7\xf3\xcb\xc7\xce\x67\x3c\xe7\xbc\x19\x4d\x0d\x6e\x34\x77\xdf\x55\x3f\x74\x28\xb0\x85\x8f\x86\xd0\x19\xd5\xcd\x53\x39\x08\xfb\x0c\x95\xb5\x5d\x0e\x3f\x38\xd3\x0d\xfc\x52\x6f\x15\xd1\x4b\x18\xb1\xa6\x27\xf9\xe8\xb4\xa1\x46\x64\x65\x59\xc2\xd3\xd7\x07\x00\xb8\xf2\xdf\x4c\x56\x00\xb6\xf2\x41\xf5\x98\x73\x76\xe0\x9c\xdd\xde\xc2\x7b\x35\xd0\xe8\x10\x3c\x6d\xec\x48\x05\x68\x03\x0a\x1a\xdd\x21\xe8\x06\x68\x8b\xe9\xdb\xbf\x78\xc2\x1e\xb4\x07\xb5\x57\xba\x53\x55\x87\x05\x58\xda\xa2\x9b\xb4\x47\x68\x9c\xed\x23\x5a\x28\xa8\xad\xf1\xb6\x43\xb0\x23\x0d\x23\x81\x6d\xe0\x28\x99\x7f\xf1\xb5\xea\xba\x02\xa6\xad\xae\xb7\xa0\x4d\xdd\x8d\x1b\xf4\xe1\x6c\x74\x4e\x72\x96\x48\xc0\xaa\x04\xeb\xe5\x3a\x0e\x38\x9b\x0a\x40\xe7\xc2\x24\x61\x3f\xdc\xeb\x0e\x45\x26\x33\xb8\x3e\xde\x05\xae\x21\x93\xa9\x52\x66\x39\x67\x7b\xe5\x5e\x39\xfc\xfe\x47\xf5\x42\xc8\xd9\x10\xf4\xf8\x62\xdf\xcf\xd3\xab\x12\x76\x5e\x7e\xe8\x6c\xa5\x3a\xf9\x01\x49\x64\xad\x7d\x3c\xdb\x12\x80\x74\x13\x0f\x7e\x53\x82\xd1\x5d\x94\x76\x29\x5a\xff\x63\x51\x11\x60\x8f\x66\x7d\xae\x76\x58\x93\x08\x76\x8a\x6a\x66\x12\xfd\x62\xec\x48\xaf\x04\x35\x0c\x68\x36\x62\x9e\x28\xa0\x92\x52\xe6\x9c\xb1\x43\x1e\x2c\x02\xec\x3c\xc6\x92\x57\x3d\xa0\x84\x29\x99\xe7\x49\xb9\xa8\x55\x48\x97\x7c\xb0\x93\xc8\x39\xb3\xcf\x50\x02\xb9\x11\x67\x77\x3b\x54\x06\xc6\x21\xd9\xba\xc1\x06\x9d\xc3\x0d\x04\x17\xc0\x5b\x98\x10\x6a\x65\xc0\x61\x6d\xf7\xe8\x8e\x8e\x63\x4a\x19\x0c\xca\xe8\xda\x4b\xce\x62\x1d\xc4\x8b\xa4\x0b\x6c\x3c\x45\x43\x9a\x9e\x7e\x1b\x9d\x22\x6d\x8d\x58\x58\xc8\xf5\x58\x89\xc8\x2e\xcf\x39\x67\x89\x87\xf5\x29\x4a\x05\x38\xf4\x64\x4f\x12\xd7\x22\xcd\x59\x91\x9c\x45\xf7\xc2\x35\xd3\xeb\xe1\x2c\xb8\x30\x41\xb9\x78\xf0\x1f\x4c\x38\x77\x3b\xc8\xc9\x92\x72\x09\x54\xd4\xcb\xc2\x89\xc4\x6c\x92\x91\xa6\x48\x05\x27\x82\xfb\x39\x89\x89\x9d\x43\xb5\x09\x29\xbc\x73\x0e\xd0\x39\xeb\x66\xfc\xe2\x6c\xa5\x7c\x1d\x89\x29\xc6\x54\xe4\x11\xf7\x4f\x88\xe1\x7e\xc2\xde\xee\x2f\x96\x74\x73\x86\x70\x92\xbb\xf4\xa8\xef\x87\xf4\xaa\x13\x35\x74\xae\x80\x6c\xee\x42\xab\x58\xa9\x4d\x3b\x73\x8d\x52\xaf\xe0\x6a\x1f\x1f\xfd\x09\x6a\x3c\x28\x5c\xee\xee\xbb\x26\xf1\x6b\x1c\x1e\x82\x0e\x7c\xd6\xbe\x51\xba\x5b\xc4\x9f\x1f\xde\x1c\x90\xa8\x4c\x6b\x63\xe8\xd2\x16\x2f\xbf\x38\xdd\xaf\x07\x55\xa3\xb0\x23\x85\xf5\x49\x99\x7f\xd9\x80\xad\xfc\x1c\x7d\xce\x93\xad\xd8\xca\xaf\xc6\xba\x0d\x86\x48\xfe\x98\x15\xf0\xd6\xd1\x27\x6d\xd0\x8b\xd6\x52\x1e\x44\x58\x66\x02\x74\x0e\x6f\xdf\xc6\x67\x59\x9e\xc9\x13\x58\xc7\x3c\xca\xf5\xac\x52\xd6\x5a\x5a\x7d\x33\xa1\xef\x45\x4a\x62\x3c\x9e\x95\xcf\xd3\x59\x01\xd1\xb6\x33\x5e\xec\x70\x91\x0a\xdd\x40\xb8\xf2\x9b\x12\x22\xcc\xff\x3b\x7d\x39\xb1\xb5\x54\x44\xa4\x13\xe9\xe3\x21\x11\xe4\x4d\x09\x59\x06\x3f\x7f\x5e\x36\x9e\xb3\xa6\x7e\x73\x73\x03\xf7\xef\x3e\x7e\x5a\xc1\x95\x07\x71\xe5\xf3\x00\xbe\xf4\xf6\x02\xc2\xf3\x2c\x22\x60\x8a\x72\xe8\x09\x8d\xea\x3c\x2e\x57\xbb\xf8\xcf\xf8\x1b\xfe\xe3\xbb\xf5\xfa\x04\xff\x12\x3d\x5f\x78\x5f\x32\x8d\x5d\x43\xa0\x3b\xee\x39\x88\x3c\xf5\xa2\xa7\xd1\x1c\x5b\x8b\xe4\x0c\x5b\x79\x1f\xf2\xe4\x90\x46\x67\xf8\x81\xff\x35\x00\x00\x6c\x7b\xed\x66\x07\x00\x00"),
 		},
 		"/src/testing/ioutil.go": &vfsgen۰CompressedFileInfo{
 			name:             "ioutil.go",
@@ -523,10 +523,10 @@ var FS = func() http.FileSystem {
 		},
 		"/src/time/time.go": &vfsgen۰CompressedFileInfo{
 			name:             "time.go",
-			modTime:          mustUnmarshalTextTime("2017-06-28T23:32:11Z"),
-			uncompressedSize: 2402,
+			modTime:          mustUnmarshalTextTime("2026-10-14T09:45:07Z"),
+			uncompressedSize: 3092,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x56\x5d\x6f\xdb\xb8\x12\x7d\x26\x7f\xc5\x54\xb8\x17\xa1\x12\x45\x4a\xda\xa2\x17\x37\x88\x17\xe8\xa6\x1f\x28\xd0\x36\xc0\x26\x7d\x69\x51\x14\x34\x35\xb2\xe9\xca\xa4\x40\x52\x4d\x62\xd7\xff\x7d\x31\xa4\x2c\xdb\x6d\xb7\xc0\xfa\x49\x1c\x0e\xe7\xcc\x39\x1c\xce\xb8\xaa\xe0\x64\xda\xeb\xb6\x86\x85\xe7\xbc\x93\xea\xab\x9c\x21\x04\xbd\x44\xce\xf5\xb2\xb3\x2e\x80\xe0\x2c\x73\xbd\x21\x5b\xc6\x39\xcb\x66\x3a\xcc\xfb\x69\xa9\xec\xb2\x9a\xd9\x6e\x8e\x6e\xe1\x77\x1f\x0b\x9f\xf1\x9c\xf3\xaa\x82\x77\xf2\x2b\x82\xef\x5d\x8a\x56\x7e\x30\xfa\x1e\x9a\xde\x28\x90\xa6\x4e\xa6\x5b\xbd\x44\xf0\xc1\xf5\x2a\x80\x0e\xe0\x30\xf4\xce\x78\x90\x0e\x41\xb6\x77\xf2\xc1\x83\x36\xaa\xed\x6b\xac\xe1\x4e\x87\x39\x84\xb9\xf6\xb0\x4d\x51\xd4\xe8\x3b\x1d\x10\x5e\x5c\xbd\xcc\x0b\x02\x9c\xa2\x92\xbd\x47\x08\x73\x7c\x38\x72\x08\x06\x91\x8e\x36\xd6\x81\x36\x01\x9d\x91\xad\x5e\xc9\xa0\xad\xa9\xf0\xfe\x60\x0d\xb6\xd9\x65\x54\xbd\x90\x01\x4b\xb8\x41\x04\xed\x7d\x8f\x30\x0f\xa1\xf3\x17\x55\xf5\x5b\xde\xd1\xd5\x57\x8f\xff\xf7\xff\x92\x47\x96\xda\xe8\x20\x72\x58\x73\x56\x55\x20\xbf\x59\x5d\x43\x8d\xb2\x06\x65\x6b\x04\x6c\xf5\x52\x9b\x88\xcd\xd9\x37\xe9\xe0\x0b\x44\x31\x26\x40\x32\x89\xb3\x02\xce\x72\xbe\xe1\x3c\x3c\x74\x08\x83\xf6\xe4\xe0\xb6\x72\xad\x39\xd3\x90\x7e\xda\x84\x27\x8f\x39\xbb\x9b\xa3\x19\x96\xcf\x9e\x72\xd6\xa1\xd3\xb6\x1e\x97\xcd\xe0\x4c\xa9\x89\xa8\x46\x23\x15\xae\x37\x05\xf4\xda\x84\x2e\xb8\x9c\x33\xe9\x66\xdb\x80\xdb\x6d\xce\x08\xd9\xf6\x01\x8e\x17\xbe\xbc\x9e\x2e\x50\x05\xce\xa4\x0a\xfa\x1b\x02\x4c\xad\x6d\x29\xcb\x91\xef\x5b\xab\x64\x9b\x48\xd7\x70\x31\x81\x85\x2f\x5f\xb7\x76\x2a\xdb\xf2\x35\x06\x91\x91\xb0\x59\x5e\xbe\xc7\x3b\x91\x73\xe6\xc9\xa3\x2e\x6f\x82\xd3\x66\x46\x06\x4d\x06\x6d\x6a\xbc\xff\xf3\x21\xa0\xf0\x05\x1c\x89\xa3\x9c\xb3\xc5\xcf\xf6\x9c\xec\xba\x01\x0d\x93\x09\x9c\x9e\xc3\xf7\xef\xb0\x18\x3e\xd7\x9c\xb1\x96\xf2\x78\x6b\x55\x69\x64\x14\x35\xfb\x70\x7b\x95\x71\xc6\x52\x85\x71\xb6\xe1\x3f\xb9\xf8\x4f\xfa\xe4\x1c\x2e\x60\xf1\x79\x6f\x6f\x65\x0d\xed\x7d\xfa\x4c\x1f\xeb\xf5\xc1\x99\x02\xea\xf2\x4a\xb6\xad\xc8\x66\x18\xe8\x6e\xc8\xe7\xba\x69\x3c\x86\x2c\x2f\xdf\x18\xba\xfc\x63\x38\x7d\x76\x56\x40\x23\x5b\x8f\x9b\xcd\x28\xd5\x70\xa1\xef\xa5\xb1\x22\x4f\x37\x44\x69\xa7\xec\x7e\x27\xda\x21\x60\x82\x79\xf6\x34\x02\xc5\x28\xe2\x9d\x6e\x5b\xed\x51\x59\x53\xe7\x23\x9c\xb1\x77\x22\x07\xe1\x51\x25\xaf\x02\xcc\xf0\xfd\xe4\x71\x01\x4b\x6b\x6c\xb2\xc7\x7b\x33\x24\xf6\x41\x82\x63\x62\x06\xaa\x01\xe6\x26\x21\x14\x29\x86\x30\xf0\xdf\xc3\x8d\xbc\x00\x33\xc2\xdf\xb4\x88\x9d\xa8\xe1\x45\xef\x62\xc1\x47\x18\x45\x30\x4b\xf9\x15\x85\x9a\x4b\x33\x54\xf5\x7a\x43\xb7\x3d\xd2\x4f\x64\xff\xe3\x13\x5b\xdb\x87\xac\x20\x71\xde\x0c\x6f\x39\x55\xa3\x88\x15\x9d\xc3\x1a\x54\x6b\x3d\x0a\x95\xc3\x26\x25\x26\xea\x6a\x5f\x8e\x9c\xb3\xcb\x53\x35\x66\xe5\x83\x74\x31\xae\x13\x01\x8e\xf7\x9f\x58\xcc\x2f\x94\x43\x91\x4f\x20\xb8\x1e\x39\xab\x75\xd3\x50\xce\x22\x94\xf1\xa5\x9d\x1e\x8a\x94\x8f\xda\x1c\x5c\x01\xd5\x68\x3c\xf9\x07\x9c\x5f\x5e\x3e\x39\xa7\xfa\x84\xaa\x82\xa5\x0c\xf3\xf2\x9d\xbc\x7f\x93\xde\xee\x7e\x61\x6e\x4f\x5c\xc2\x59\xac\xe5\xb8\x98\xc0\x59\xdc\x0c\xe5\xf6\x3d\xee\x3f\xae\x7f\x27\x14\x67\xfb\xec\x62\x6d\x72\x46\xb0\xa1\x1c\x9a\xc6\xa3\xc9\x80\xcd\x06\xb2\x27\x93\x71\x93\xac\xfb\xda\xe5\x9c\x51\x62\x6c\x66\x21\x94\x8d\x08\xa5\x74\xb3\xd8\xbd\x18\x5d\x03\x25\x7f\x72\x9e\xef\xa9\x6e\xbb\x7f\x10\x9d\x9a\x09\x81\xfe\x48\x4b\xb5\x28\xdd\x8e\xd7\xa8\x40\xce\xd9\x9d\xf4\xcf\x13\x8f\x0b\x4a\x30\x71\xe2\xbf\x60\x37\x14\xf0\xe8\x3f\xe6\xd3\x5a\x59\x53\xd3\xa2\xba\x14\xb1\x13\xf8\xd8\x8e\x72\x10\xc7\x5b\x7b\x01\xe8\x9c\x4d\x65\x31\x04\xa2\x63\x1f\xad\xc1\x57\xba\x45\x31\xd0\x28\x5f\x5f\xff\x75\x7d\x7d\x2b\xf2\x93\xac\x6a\xf5\xb4\x22\x5b\x45\x3d\x41\x9b\xc6\x96\x2b\xdd\x65\x05\x10\xc2\x4e\x8c\xc6\x3a\x85\x1f\x75\x47\x51\x5e\x59\x77\x8b\x3e\x50\x27\x5c\xe9\xee\xda\xb4\x0f\x51\x10\x02\xdd\x6f\xb0\x83\x0f\x61\xa7\xab\x5c\xc5\xec\x88\xff\x01\x95\xec\xf9\x12\x9d\x56\xb2\x7a\x6b\xfd\x97\xe7\x66\x86\x2d\xfa\x2c\x95\x23\xb9\x3f\x9a\x80\xd1\x51\x6d\xd6\x49\xa3\x95\xc8\x94\x34\xc6\x86\x18\x04\x7e\x71\x36\x0e\xd1\x90\xc0\x2f\x20\x83\x13\x0a\x53\xbe\x24\x5d\x04\xbd\xac\x0d\x67\xab\xb1\xd9\xc6\x29\x90\xed\xda\x28\x4c\xe0\x78\x45\x34\xaa\x6a\xd7\xc6\x41\x7b\x50\xb6\xd3\x34\xa0\x9d\x5d\x0e\xba\xef\xc6\x7b\xb0\xc3\xd0\x4c\x7f\x42\xb4\x99\xd1\x5f\x04\xe1\xb5\x51\x71\xc2\x83\x43\xd9\xc6\xa1\x3d\x1e\xa9\x2d\x7a\x73\x14\xf2\x71\x00\x8f\x13\x63\x88\x5e\x80\x82\xe9\x43\xc0\xd8\x73\x0f\x3b\xee\x0f\x6f\xc5\x6f\x5b\x6d\x0c\x72\xdd\xa4\x07\xb5\xdf\x96\xd3\xd8\xca\xb6\x7e\xc4\xe1\x6a\x2e\xdd\x95\xad\x31\x2b\x40\xe5\xc3\x08\xe0\x1b\xfe\x77\x00\x00\x00\xff\xff\xaf\xc7\x54\x30\x62\x09\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x56\xdf\x6f\xdb\xb0\x11\x7e\x16\xff\x8a\xab\xb0\x35\x54\xa2\x48\x49\x5b\x64\x58\x16\x0f\xe8\xd2\x1f\x08\xd0\x36\xc0\x92\xbe\xb4\x28\x0a\x9a\x3a\xd9\x74\x64\x52\x20\xa9\x3a\x8e\xeb\xff\x7d\x38\x52\x96\xed\x26\xed\xb0\xf9\xc9\x22\x8f\xc7\xef\xfb\xee\x78\x77\x65\x09\x47\xe3\x4e\x35\x15\xcc\x1c\x63\xad\x90\x77\x62\x82\xe0\xd5\x1c\x19\x53\xf3\xd6\x58\x0f\x9c\x25\xa9\xed\x34\xad\xa5\x8c\x25\xe9\x44\xf9\x69\x37\x2e\xa4\x99\x97\x13\xd3\x4e\xd1\xce\xdc\xf6\xcf\xcc\xa5\x2c\x63\xac\x2c\xe1\xa3\xb8\x43\x70\x9d\x8d\xde\x8a\xcf\x5a\xdd\x43\xdd\x69\x09\x42\x57\x71\xe9\x56\xcd\x11\x9c\xb7\x9d\xf4\xa0\x3c\x58\xf4\x9d\xd5\x0e\x84\x45\x10\xcd\x42\x2c\x1d\x28\x2d\x9b\xae\xc2\x0a\x16\xca\x4f\xc1\x4f\x95\x83\x0d\x44\x5e\xa1\x6b\x95\x47\x78\x73\xf9\x36\xcb\xe9\xc2\x31\x4a\xd1\x39\x04\x3f\xc5\xe5\x81\x45\xd0\x88\x74\xb4\x36\x16\x94\xf6\x68\xb5\x68\xd4\x83\xf0\xca\xe8\x12\xef\xf7\xbe\xc1\xd4\x5b\x44\xe5\x1b\xe1\xb1\x80\x1b\x44\x50\xce\x75\x08\x53\xef\x5b\x77\x5e\x96\x7f\xe4\x1d\x4c\x5d\xf9\xe2\x6f\x7f\x2f\x58\x60\xa9\xb4\xf2\x3c\x83\x15\x4b\xca\x12\xc4\x0f\xa3\x2a\xa8\x50\x54\x20\x4d\x85\x80\x8d\x9a\x2b\x1d\xee\x66\xc9\x0f\x61\xe1\x3b\x04\x31\x46\x40\x32\xf1\x93\x1c\x4e\x32\xb6\x66\xcc\x2f\x5b\x84\x5e\x7b\x32\xb0\x1b\xb9\x56\x2c\x51\x10\x7f\x4a\xfb\x97\x2f\x58\xb2\x98\xa2\xee\x3f\xcf\x5e\xb1\xa4\x45\xab\x4c\x35\x7c\xd6\xbd\x31\x41\xe3\x41\x8d\x5a\x48\x5c\xad\x73\xe8\x94\xf6\xad\xb7\x19\x4b\x84\x9d\x6c\x1c\x6e\xb6\x59\x42\x37\x9b\xce\xc3\xe1\xcc\x15\xd7\xe3\x19\x4a\xcf\x12\x21\xbd\xfa\x81\x00\x63\x63\x1a\x42\x39\xf0\xfd\x60\xa4\x68\x22\xe9\x0a\xce\x47\x30\x73\xc5\xfb\xc6\x8c\x45\x53\xbc\x47\xcf\x53\x12\x36\xcd\x8a\x4f\xb8\xe0\x19\x4b\x1c\x59\x54\xc5\x8d\xb7\x4a\x4f\x68\x41\xd1\x82\xd2\x15\xde\xff\x6b\xe9\x91\xbb\x1c\x0e\xf8\x41\xc6\x92\xd9\xe3\xf5\x8c\xd6\x55\x0d\x0a\x46\x23\x38\x3e\x85\x9f\x3f\x61\xd6\xff\x5d\xb1\x24\x69\x08\xc7\x07\x23\x0b\x2d\x82\xa8\xe9\xe7\xdb\xcb\x94\x25\x49\xcc\x30\x96\xac\xd9\x23\x13\xf7\x55\x1d\x9d\xc2\x39\xcc\xbe\xed\xec\x3d\x18\x4d\x7b\x5f\xbf\xd1\x9f\xd5\x6a\xef\x4c\x0e\x55\x71\x29\x9a\x86\xa7\x13\xf4\x14\x1b\xb2\xb9\xae\x6b\x87\x3e\xcd\x8a\x2b\x4d\xc1\x3f\x84\xe3\xb3\x93\x1c\x6a\xd1\x38\x5c\xaf\x49\xaa\xb2\xdc\x84\xf3\x93\xd0\x66\x9b\xf2\x30\x37\xda\x78\xa3\x95\x04\xd9\x18\x79\x07\x16\x45\xa5\xf4\xa4\x80\x2b\x0f\xad\xc5\x1a\xad\x83\xd6\x1a\x89\xce\x15\x53\x4b\x0e\xa0\xd3\x15\x5a\xf2\xf8\xc9\x54\x58\xcc\x5c\x78\x58\x2d\xda\xda\xd8\xb9\xd0\x12\x0b\x6d\x16\xa0\x34\x8c\xad\x59\x38\xb4\x2e\x07\xa7\xb4\x44\xa0\x30\x80\xd1\xcd\x12\xa6\xc2\xc1\x5c\x35\x8d\x72\x28\x8d\xae\x02\x3a\x74\xa6\xe9\xc2\xa3\x20\x77\x73\xb1\x84\x59\x37\x6f\x21\xa4\x97\x9f\x22\xb8\xa5\xf3\x38\xef\x51\x2a\x07\xa2\x9a\x75\xce\x63\xd5\xa7\xfd\x0e\x3b\x9e\xc5\xec\x0b\xc9\x5a\x6f\xd0\x3f\x91\x17\xfd\x4e\x9a\xfd\x63\x30\x7a\x16\x8c\x3e\xeb\x0a\x6b\xa5\xb1\x82\xe7\xcf\x07\xf6\xe1\x48\x94\x20\xcd\x1e\x19\x52\xf8\x3d\xdd\xb1\x31\x8f\x31\xda\xd8\x0f\x49\x00\xbe\xb8\xa2\x94\xe2\x27\x21\x58\x67\xaf\x78\x76\x18\xd0\xf2\x9b\xa0\x45\x06\x47\x83\xc9\xe9\x60\x12\x52\x47\xd5\xbb\x2a\x3f\xc5\x67\xbb\x1b\x38\x6d\x3f\x9f\xe4\xb5\xdd\x8e\xc7\xb5\x59\xfc\x86\x58\x0f\x3d\xe2\xdc\x3d\x17\x49\x86\x83\xc5\xbb\xc6\x88\x98\x7c\x35\xfd\x3b\x7b\xc5\x3f\x6e\x43\x9c\x45\x0a\xbd\xa3\x3f\xbc\xcf\xfd\xdc\x4e\x07\x05\xe0\xb0\xbf\x7e\xd7\xe9\x50\x04\xb4\x59\xf0\x0c\xb8\x43\x19\xad\x72\xd0\xfd\xff\x97\x2f\xf2\x90\xe3\x71\x3d\x94\x08\xfd\x5f\x4a\xc4\xff\x06\x61\xc3\x49\x43\x09\x7b\x81\xcc\xe3\xf5\x5c\xc3\x5f\xf7\x37\xb2\x7c\x3f\x5b\x07\x16\x37\x0d\x62\xcb\x2b\x78\xd3\xd9\x50\xa2\x03\x5a\x49\x68\xe7\xe2\x0e\xb9\x9c\x0a\xdd\xd7\xe1\xd5\x9a\xea\xd3\x40\x21\x02\xfe\x8b\x8b\x88\x4d\xe7\xd3\x9c\x08\x5e\xf5\xdd\x27\xd6\x4f\x1e\x6a\x70\x06\x2b\x7a\x43\x0e\xb9\xcc\x60\x1d\x41\xf2\xaa\xfc\x25\x54\x17\xc7\x72\x40\xe5\xbc\xb0\xc1\xaf\xe5\x1e\x0e\x77\x9b\x42\xc0\xe7\x8b\xbe\x2c\x8f\xc0\xdb\x0e\x59\x52\xa9\xba\x26\xcc\xdc\x17\xe1\xf1\x1e\xef\xb3\xcd\x06\x9d\xf6\x65\x54\x35\x84\x93\xff\x84\xd3\x8b\x8b\x97\xa7\x54\x51\xa1\x2c\x61\x2e\xfc\xb4\xf8\x28\xee\xaf\x62\xb7\xd9\x2d\xa5\x9b\x13\x17\x70\x12\xb2\x34\x7c\x8c\xe0\x24\x6c\xfa\x62\xd3\x41\x46\xf0\xff\x0a\xc5\x92\x5d\x76\xa1\x9a\xb2\x84\xae\xf5\x45\xdf\xe6\x9e\x8d\xfa\xbb\x93\x9e\xec\xd1\x68\xd8\xa4\xd5\x5d\xed\xa8\x06\xac\x59\x92\x4c\x0c\xf8\xa2\xe6\xbe\x10\x76\x12\xfa\x6d\x42\x61\x20\xf0\x47\xa7\xd9\x8e\xea\xa6\xfd\x8d\xe8\xd4\xfe\xe8\xd2\x5f\x69\xc9\x06\x85\xdd\xf2\x1a\x14\xc8\x58\xb2\x10\xee\x75\xe4\x71\x3e\x82\x0d\x27\xf6\x04\xbb\x3e\x99\x07\xfb\x01\x4f\x63\x44\x45\x6d\x96\xf2\x92\x87\xde\xe5\x42\x03\xcd\x80\x1f\x6e\xd6\x73\x40\x6b\x4d\x4c\x8b\xde\x11\x1d\xfb\x62\x34\xbe\x53\x0d\xf2\x9e\x46\xf1\xfe\xfa\xdf\xd7\xd7\xb7\x3c\x3b\x4a\xcb\x46\x8d\x4b\x5a\x2b\xa9\x8b\x29\x5d\x9b\xe2\x41\xb5\x69\x0e\x74\xc3\x56\x8c\xda\x58\x89\x5f\x54\x4b\x5e\xde\x19\x7b\x8b\xce\x53\xef\x7e\x50\xed\x35\xb5\x13\x12\x84\x2e\xdd\x1d\x09\x7a\x1b\xba\x3b\x86\xf2\x21\xa0\x23\xfe\x7b\x54\xd2\xd7\x73\xb4\x4a\x8a\xf2\x83\x71\xdf\x5f\xeb\x09\x36\xe8\xd2\x98\x8e\x64\xfe\x6c\x04\x5a\x05\xb5\x93\x56\x68\x25\x79\x2a\x85\xd6\xc6\x07\x27\xf0\xc4\x59\xc2\x0a\x3e\x5e\x7e\x0e\x29\x1c\x91\x9b\xe2\x2d\xe9\xc2\xfb\x22\xf8\x30\x8c\x07\x61\x6e\x49\xb7\x8d\x1f\x46\x70\xf8\xd0\xb7\xeb\x61\xf0\x00\xe5\x40\x9a\x56\xd1\x48\x69\xcd\xbc\xd7\x7d\x3b\x90\x7a\xd3\x8f\x79\x71\x6c\x56\x7a\x02\xca\x03\x8f\x3d\x97\x3a\xa7\x45\xd1\x84\x31\x73\x38\x52\x19\x74\xfa\xc0\x67\xc3\xc8\x38\xcc\x38\xbd\xf7\x1c\x24\x8c\x97\x1e\x43\x27\xdd\x09\xe7\xe3\xb7\xe2\x36\xe5\x32\x38\xb9\xae\xe3\x83\xda\x2d\xad\x71\xd0\x4a\x37\x76\xc4\xe1\x72\x2a\xec\xa5\xa9\x30\xcd\x41\x66\xfd\xd0\xc2\xd6\xec\x3f\x03\x00\x79\xd4\xb3\x04\x14\x0c\x00\x00"),
 		},
 		"/src/unicode": &vfsgen۰DirInfo{
 			name:    "unicode",
@@ -44,10 +44,6 @@ func Cbrt(x float64) float64 {
 	return math.Call("cbrt", x).Float()
 }
 
-func Ceil(x float64) float64 {
-	return math.Call("ceil", x).Float()
-}
-
 func Copysign(x, y float64) float64 {
 	if (x < 0 || 1/x == negInf) != (y < 0 || 1/y == negInf) {
 		return -x
@@ -87,10 +83,6 @@ func Expm1(x float64) float64 {
 	return expm1(x)
 }
 
-func Floor(x float64) float64 {
-	return math.Call("floor", x).Float()
-}
-
 func Frexp(f float64) (frac float64, exp int) {
 	return frexp(f)
 }
@@ -208,10 +200,6 @@ func Sincos(x float64) (sin, cos float64) {
 	return Sin(x), Cos(x)
 }
 
-func Sqrt(x float64) float64 {
-	return math.Call("sqrt", x).Float()
-}
-
 func Tan(x float64) float64 {
 	return math.Call("tan", x).Float()
 }
@@ -226,37 +214,3 @@ func Trunc(x float64) float64 {
 	}
 	return float64(int(x))
 }
-
-var buf struct {
-	uint32array  [2]uint32
-	float32array [2]float32
-	float64array [1]float64
-}
-
-func init() {
-	ab := js.Global.Get("ArrayBuffer").New(8)
-	js.InternalObject(buf).Set("uint32array", js.Global.Get("Uint32Array").New(ab))
-	js.InternalObject(buf).Set("float32array", js.Global.Get("Float32Array").New(ab))
-	js.InternalObject(buf).Set("float64array", js.Global.Get("Float64Array").New(ab))
-}
-
-func Float32bits(f float32) uint32 {
-	buf.float32array[0] = f
-	return buf.uint32array[0]
-}
-
-func Float32frombits(b uint32) float32 {
-	buf.uint32array[0] = b
-	return buf.float32array[0]
-}
-
-func Float64bits(f float64) uint64 {
-	buf.float64array[0] = f
-	return uint64(buf.uint32array[1])<<32 + uint64(buf.uint32array[0])
-}
-
-func Float64frombits(b uint64) float64 {
-	buf.uint32array[0] = uint32(b)
-	buf.uint32array[1] = uint32(b >> 32)
-	return buf.float64array[0]
-}
@@ -50,6 +50,14 @@ func runtime_canSpin(i int) bool {
 
 // Copy of time.runtimeNano.
 func runtime_nanotime() int64 {
+	const second = 1000000000
 	const millisecond = 1000000
+	if process := js.Global.Get("process"); process != js.Undefined && process.Get("hrtime") != js.Undefined {
+		t := process.Call("hrtime")
+		return t.Index(0).Int64()*second + t.Index(1).Int64()
+	}
+	if performance := js.Global.Get("performance"); performance != js.Undefined && performance.Get("now") != js.Undefined {
+		return int64(performance.Call("now").Float() * millisecond)
+	}
 	return js.Global.Get("Date").New().Call("getTime").Int64() * millisecond
 }
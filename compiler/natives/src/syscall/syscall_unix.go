@@ -60,12 +60,20 @@ func Syscall(trap, a1, a2, a3 uintptr) (r1, r2 uintptr, err Errno) {
 		return uintptr(r.Index(0).Int()), uintptr(r.Index(1).Int()), Errno(r.Index(2).Int())
 	}
 	if trap == SYS_WRITE && (a1 == 1 || a1 == 2) {
+		if n, err, ok := writeStd(int(a1), a2, int(a3)); ok {
+			return uintptr(n), 0, err
+		}
 		array := js.InternalObject(a2)
 		slice := make([]byte, array.Length())
 		js.InternalObject(slice).Set("$array", array)
 		printToConsole(slice)
 		return uintptr(array.Length()), 0, 0
 	}
+	if trap == SYS_READ && a1 == 0 {
+		if n, err, ok := readStdin(a2, int(a3)); ok {
+			return uintptr(n), 0, err
+		}
+	}
 	if trap == SYS_EXIT {
 		runtime.Goexit()
 	}
@@ -73,6 +81,121 @@ func Syscall(trap, a1, a2, a3 uintptr) (r1, r2 uintptr, err Errno) {
 	return uintptr(minusOne), 0, EACCES
 }
 
+// readStdin reads up to n bytes from the standard input of Node.js into the
+// array p, without the syscall module. It returns false if not running on
+// Node.js.
+func readStdin(p uintptr, n int) (r int, err Errno, ok bool) {
+	require := js.Global.Get("require")
+	if require == js.Undefined {
+		return 0, 0, false
+	}
+	fs := nodeFS(require)
+	if fs == nil {
+		return 0, 0, false
+	}
+	if n == 0 {
+		return 0, 0, true
+	}
+	array := js.InternalObject(p)
+	buf := js.Global.Get("Buffer").Call("from", array.Get("buffer"), array.Get("byteOffset"), n)
+	for {
+		again := false
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					jsErr, isJsErr := e.(*js.Error)
+					if !isJsErr {
+						panic(e)
+					}
+					switch jsErr.Get("code").String() {
+					case "EOF":
+						r = 0
+					case "EAGAIN":
+						again = true // stdin is non-blocking, e.g. for some terminals.
+					default:
+						r, err = minusOne, EIO
+					}
+				}
+			}()
+			r = fs.Call("readSync", 0, buf, 0, n, nil).Int()
+		}()
+		if !again {
+			return r, err, true
+		}
+	}
+}
+
+// writeStd writes n bytes from the array p to the standard output or error of
+// Node.js, fd 1 or 2, without the syscall module. It returns false if not
+// running on Node.js, or if the output is captured with goPrintToConsole, in
+// which case it is written to the console.
+func writeStd(fd int, p uintptr, n int) (r int, err Errno, ok bool) {
+	require := js.Global.Get("require")
+	if require == js.Undefined || js.Global.Get("goPrintToConsole") != js.Undefined {
+		return 0, 0, false
+	}
+	fs := nodeFS(require)
+	if fs == nil {
+		return 0, 0, false
+	}
+	if n == 0 {
+		return 0, 0, true
+	}
+	array := js.InternalObject(p)
+	buf := js.Global.Get("Buffer").Call("from", array.Get("buffer"), array.Get("byteOffset"), n)
+	for {
+		again := false
+		func() {
+			defer func() {
+				if e := recover(); e != nil {
+					jsErr, isJsErr := e.(*js.Error)
+					if !isJsErr {
+						panic(e)
+					}
+					switch jsErr.Get("code").String() {
+					case "EAGAIN":
+						again = true // the output is non-blocking, e.g. for some pipes.
+					case "EPIPE":
+						r, err = minusOne, EPIPE
+					default:
+						r, err = minusOne, EIO
+					}
+				}
+			}()
+			r = fs.Call("writeSync", fd, buf, 0, n).Int()
+		}()
+		if !again {
+			return r, err, true
+		}
+	}
+}
+
+// nodeFS returns the fs module of Node.js, or nil if require doesn't provide
+// it with synchronous writes, like the shims of bundlers for browsers.
+func nodeFS(require *js.Object) (fs *js.Object) {
+	defer func() {
+		if recover() != nil {
+			fs = nil
+		}
+	}()
+	fs = require.Invoke("fs")
+	if fs == js.Undefined || fs == nil || fs.Get("writeSync") == js.Undefined || js.Global.Get("Buffer") == js.Undefined {
+		return nil
+	}
+	return fs
+}
+
+// Exit exits the Node.js process with the status code after flushing the
+// console, without the syscall module. Elsewhere, it stops the calling
+// goroutine, which ends the program if it is the main goroutine.
+func Exit(code int) {
+	if process := js.Global.Get("process"); process != js.Undefined {
+		js.Global.Call("$flushConsole")
+		process.Call("exit", code)
+	}
+	runtime.Goexit()
+}
+
 func Syscall6(trap, a1, a2, a3, a4, a5, a6 uintptr) (r1, r2 uintptr, err Errno) {
 	if f := syscall("Syscall6"); f != nil {
 		r := f.Invoke(trap, a1, a2, a3, a4, a5, a6)
@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/gopherjs/gopherjs/js"
 )
 
 func runExample(eg InternalExample) (ok bool) {
@@ -14,14 +16,19 @@ func runExample(eg InternalExample) (ok bool) {
 		fmt.Printf("=== RUN   %s\n", eg.Name)
 	}
 
-	// Capture stdout.
+	// Capture stdout, in a file if the file system is available, otherwise from
+	// the console output of package syscall, which includes stderr.
 	stdout := os.Stdout
 	w, err := tempFile("." + eg.Name + ".stdout.")
+	var console []byte
+	printToConsole := js.Global.Get("goPrintToConsole")
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		js.Global.Set("goPrintToConsole", js.InternalObject(func(b []byte) {
+			console = append(console, b...)
+		}))
+	} else {
+		os.Stdout = w
 	}
-	os.Stdout = w
 
 	start := time.Now()
 	ok = true
@@ -31,13 +38,20 @@ func runExample(eg InternalExample) (ok bool) {
 		dstr := fmtDuration(time.Now().Sub(start))
 
 		// Close file, restore stdout, get output.
-		w.Close()
-		os.Stdout = stdout
-		out, readFileErr := readFile(w.Name())
-		_ = os.Remove(w.Name())
-		if readFileErr != nil {
-			fmt.Fprintf(os.Stderr, "testing: reading stdout file: %v\n", readFileErr)
-			os.Exit(1)
+		var out string
+		if w == nil {
+			js.Global.Set("goPrintToConsole", printToConsole)
+			out = string(console)
+		} else {
+			w.Close()
+			os.Stdout = stdout
+			var readFileErr error
+			out, readFileErr = readFile(w.Name())
+			_ = os.Remove(w.Name())
+			if readFileErr != nil {
+				fmt.Fprintf(os.Stderr, "testing: reading stdout file: %v\n", readFileErr)
+				os.Exit(1)
+			}
 		}
 
 		var fail string
@@ -38,13 +38,23 @@ func initLocal() {
 	localLoc.zone = []zone{{localLoc.name, d.Call("getTimezoneOffset").Int() * -60, false}}
 }
 
+// runtimeNano returns a monotonic clock reading. It prefers process.hrtime under
+// Node.js and performance.now in browsers, since Date only has millisecond
+// resolution and may jump when the system clock is adjusted.
 func runtimeNano() int64 {
+	if process := js.Global.Get("process"); process != js.Undefined && process.Get("hrtime") != js.Undefined {
+		t := process.Call("hrtime")
+		return t.Index(0).Int64()*int64(Second) + t.Index(1).Int64()
+	}
+	if performance := js.Global.Get("performance"); performance != js.Undefined && performance.Get("now") != js.Undefined {
+		return int64(performance.Call("now").Float() * float64(Millisecond))
+	}
 	return js.Global.Get("Date").New().Call("getTime").Int64() * int64(Millisecond)
 }
 
 func now() (sec int64, nsec int32, mono int64) {
-	n := runtimeNano()
-	return n / int64(Second), int32(n % int64(Second)), n
+	n := js.Global.Get("Date").New().Call("getTime").Int64() * int64(Millisecond)
+	return n / int64(Second), int32(n % int64(Second)), runtimeNano()
 }
 
 func Sleep(d Duration) {
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/gopherjs/gopherjs/compiler/analysis"
+	"github.com/gopherjs/gopherjs/compiler/prelude"
 	"github.com/neelance/astrewrite"
 	"golang.org/x/tools/go/gcimporter15"
 	"golang.org/x/tools/go/types/typeutil"
@@ -33,6 +34,11 @@ type pkgContext struct {
 	minify       bool
 	fileSet      *token.FileSet
 	errList      ErrorList
+
+	optLevel      int
+	inlines       map[*types.Func]string
+	importContext *ImportContext
+	race          bool
 }
 
 func (p *pkgContext) SelectionOf(e *ast.SelectorExpr) (selection, bool) {
@@ -90,6 +96,16 @@ type flowData struct {
 	endCase   int
 }
 
+// ImportContext resolves the imports of the package passed to Compile.
+//
+// Import is called with an import path and must return the archive of the
+// imported package, which has to be compiled before the importing package.
+// It may be called several times for the same path, during type checking and
+// again while translating, and should return the same archive each time.
+// Packages holds the type information of already imported packages by import
+// path; it is populated from the export data of archives returned by Import
+// that have no entry yet, so callers may supply archives that were compiled
+// elsewhere. Compile adds the compiled package to Packages.
 type ImportContext struct {
 	Packages map[string]*types.Package
 	Import   func(string) (*Archive, error)
@@ -115,10 +131,21 @@ func (pi packageImporter) Import(path string) (*types.Package, error) {
 		return nil, err
 	}
 
-	return pi.importContext.Packages[a.ImportPath], nil
+	if pkg, ok := pi.importContext.Packages[a.ImportPath]; ok {
+		return pkg, nil
+	}
+	_, pkg, err := gcimporter.BImportData(token.NewFileSet(), pi.importContext.Packages, a.ExportData, a.ImportPath)
+	if err != nil {
+		return nil, err
+	}
+	pi.importContext.Packages[a.ImportPath] = pkg
+	return pkg, nil
 }
 
-func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, importContext *ImportContext, minify bool) (*Archive, error) {
+// Compile compiles the package importPath made of files. With optLevel 1 or
+// above, calls of small functions are inlined. With race, accesses of
+// package level variables are instrumented to report data races, see race.go.
+func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, importContext *ImportContext, minify bool, optLevel int, race bool) (*Archive, error) {
 	typesInfo := &types.Info{
 		Types:      make(map[ast.Expr]types.TypeAndValue),
 		Defs:       make(map[*ast.Ident]types.Object),
@@ -150,18 +177,16 @@ func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, impor
 		return nil, importError
 	}
 	if errList != nil {
-		if len(errList) > 10 {
-			pos := token.NoPos
-			if last, ok := errList[9].(types.Error); ok {
-				pos = last.Pos
-			}
-			errList = append(errList[:10], types.Error{Fset: fileSet, Pos: pos, Msg: "too many errors"})
-		}
 		return nil, errList
 	}
 	if err != nil {
 		return nil, err
 	}
+	if _, isFunc := typesPkg.Scope().Lookup("main").(*types.Func); typesPkg.Name() == "main" && !isFunc {
+		// The type checker leaves this to the linker, but the program would
+		// only fail once it is started.
+		return nil, ErrorList{types.Error{Fset: fileSet, Pos: files[0].Name.Pos(), Msg: "function main is undeclared in the main package"}}
+	}
 	importContext.Packages[importPath] = typesPkg
 
 	exportData := gcimporter.BExportData(nil, typesPkg)
@@ -203,6 +228,11 @@ func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, impor
 			dependencies: make(map[types.Object]bool),
 			minify:       minify,
 			fileSet:      fileSet,
+
+			optLevel:      optLevel,
+			inlines:       make(map[*types.Func]string),
+			importContext: importContext,
+			race:          race,
 		},
 		allVars:     make(map[string]int),
 		flowDatas:   map[*types.Label]*flowData{nil: {}},
@@ -216,7 +246,11 @@ func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, impor
 	// imports
 	var importDecls []*Decl
 	var importedPaths []string
-	for _, importedPkg := range typesPkg.Imports() {
+	// Sort imports by path, so that the variable names of packages with the
+	// same name don't depend on the order in which they were imported.
+	imports := append([]*types.Package(nil), typesPkg.Imports()...)
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path() < imports[j].Path() })
+	for _, importedPkg := range imports {
 		if importedPkg == types.Unsafe {
 			// Prior to Go 1.9, unsafe import was excluded by Imports() method,
 			// but now we do it here to maintain previous behavior.
@@ -283,6 +317,19 @@ func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, impor
 		}
 	}
 
+	// inline templates
+	var inlines map[string]string
+	if optLevel >= 1 {
+		inlines = make(map[string]string)
+		for _, fun := range functions {
+			if template, ok := c.inlineTemplate(fun); ok {
+				o := c.p.Defs[fun.Name].(*types.Func)
+				c.p.inlines[o] = template
+				inlines[o.FullName()] = template
+			}
+		}
+	}
+
 	collectDependencies := func(f func()) []string {
 		c.p.dependencies = make(map[types.Object]bool)
 		f()
@@ -400,9 +447,6 @@ func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, impor
 		funcDecls = append(funcDecls, &d)
 	}
 	if typesPkg.Name() == "main" {
-		if mainFunc == nil {
-			return nil, fmt.Errorf("missing main function")
-		}
 		id := c.newIdent("", types.NewSignature(nil, nil, nil, false))
 		c.p.Uses[id] = mainFunc
 		call := &ast.CallExpr{Fun: id}
@@ -479,16 +523,12 @@ func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, impor
 				var ptrMethods []string
 				for i := 0; i < named.NumMethods(); i++ {
 					method := named.Method(i)
-					name := method.Name()
-					if reservedKeywords[name] {
-						name += "$"
-					}
 					pkgPath := ""
 					if !method.Exported() {
 						pkgPath = method.Pkg().Path()
 					}
 					t := method.Type().(*types.Signature)
-					entry := fmt.Sprintf(`{prop: "%s", name: "%s", pkg: "%s", typ: $funcType(%s)}`, name, method.Name(), pkgPath, c.initArgs(t))
+					entry := fmt.Sprintf(`{prop: "%s", name: "%s", pkg: "%s", typ: $funcType(%s)}`, methodProp(method), method.Name(), pkgPath, c.initArgs(t))
 					if _, isPtr := t.Recv().Type().(*types.Pointer); isPtr {
 						ptrMethods = append(ptrMethods, entry)
 						continue
@@ -545,6 +585,7 @@ func Compile(importPath string, files []*ast.File, fileSet *token.FileSet, impor
 		Declarations: allDecls,
 		FileSet:      encodedFileSet.Bytes(),
 		Minified:     minify,
+		Inlines:      inlines,
 	}, nil
 }
 
@@ -562,7 +603,7 @@ func (c *funcContext) initArgs(ty types.Type) string {
 			if !method.Exported() {
 				pkgPath = method.Pkg().Path()
 			}
-			methods[i] = fmt.Sprintf(`{prop: "%s", name: "%s", pkg: "%s", typ: $funcType(%s)}`, method.Name(), method.Name(), pkgPath, c.initArgs(method.Type()))
+			methods[i] = fmt.Sprintf(`{prop: "%s", name: "%s", pkg: "%s", typ: $funcType(%s)}`, methodProp(method), method.Name(), pkgPath, c.initArgs(method.Type()))
 		}
 		return fmt.Sprintf("[%s]", strings.Join(methods, ", "))
 	case *types.Map:
@@ -607,6 +648,9 @@ func (c *funcContext) translateToplevelFunction(fun *ast.FuncDecl, info *analysi
 
 	var joinedParams string
 	primaryFunction := func(funcRef string) []byte {
+		if intrinsic, ok := prelude.Intrinsics[o.FullName()]; ok && fun.Recv == nil {
+			return []byte(fmt.Sprintf("\t%s = %s;\n", funcRef, intrinsic))
+		}
 		if fun.Body == nil {
 			return []byte(fmt.Sprintf("\t%s = function() {\n\t\t$throwRuntimeError(\"native function not implemented: %s\");\n\t};\n", funcRef, o.FullName()))
 		}
@@ -16,6 +16,9 @@ var $callDeferred = function(deferred, jsErr, fromPanic) {
     throw jsErr;
   }
   if (jsErr !== null) {
+    if ($isStackOverflow(jsErr)) {
+      throw jsErr; /* a fatal error in Go, which doesn't run deferred calls and can't be recovered */
+    }
     var newErr = null;
     try {
       $curGoroutine.deferStack.push(deferred);
@@ -51,17 +54,9 @@ var $callDeferred = function(deferred, jsErr, fromPanic) {
           if (localPanicValue.Object instanceof Error) {
             throw localPanicValue.Object;
           }
-          var msg;
-          if (localPanicValue.constructor === $String) {
-            msg = localPanicValue.$val;
-          } else if (localPanicValue.Error !== undefined) {
-            msg = localPanicValue.Error();
-          } else if (localPanicValue.String !== undefined) {
-            msg = localPanicValue.String();
-          } else {
-            msg = localPanicValue;
-          }
-          throw new Error(msg);
+          var err = new Error($panicMessage(localPanicValue));
+          err.$goPanic = true;
+          throw err;
         }
       }
       var call = deferred.pop();
@@ -98,6 +93,123 @@ var $callDeferred = function(deferred, jsErr, fromPanic) {
   }
 };
 
+/* $panicMessage formats a panic value like the Go runtime does when a panic isn't recovered. */
+var $panicMessage = function(v) {
+  if (v === $ifaceNil || v === null || v === undefined) {
+    return "nil";
+  }
+  if (v.constructor === $String) {
+    return v.$val;
+  }
+  if (v.Error !== undefined) {
+    return v.Error();
+  }
+  if (v.String !== undefined) {
+    return v.String();
+  }
+  var t = v.constructor, s;
+  switch (t.kind) {
+  case $kindBool:
+  case $kindInt:
+  case $kindInt8:
+  case $kindInt16:
+  case $kindInt32:
+  case $kindUint:
+  case $kindUint8:
+  case $kindUint16:
+  case $kindUint32:
+  case $kindUintptr:
+    s = String(v.$val);
+    break;
+  case $kindInt64:
+  case $kindUint64:
+    s = $int64String(v.$val, t.kind === $kindInt64);
+    break;
+  case $kindFloat32:
+  case $kindFloat64:
+    s = $floatString(v.$val);
+    break;
+  case $kindComplex64:
+  case $kindComplex128:
+    s = "(" + $floatString(v.$val.$real) + $floatString(v.$val.$imag) + "i)";
+    break;
+  case $kindString:
+    s = JSON.stringify(v.$val);
+    break;
+  default:
+    return "(" + t.string + ")";
+  }
+  return t.pkg === "" ? s : t.string + "(" + s + ")";
+};
+/* $floatString formats f like printfloat of the Go runtime, with 7 significant digits and a 3-digit exponent. */
+var $floatString = function(f) {
+  if (f !== f) {
+    return "NaN";
+  }
+  if (f === 1/0) {
+    return "+Inf";
+  }
+  if (f === -1/0) {
+    return "-Inf";
+  }
+  var n = 7, sign = "+", e = 0;
+  if (f === 0) {
+    if (1/f < 0) {
+      sign = "-";
+    }
+  } else {
+    if (f < 0) {
+      f = -f;
+      sign = "-";
+    }
+    while (f >= 10) {
+      e++;
+      f /= 10;
+    }
+    while (f < 1) {
+      e--;
+      f *= 10;
+    }
+    var h = 5.0;
+    for (var i = 0; i < n; i++) {
+      h /= 10;
+    }
+    f += h;
+    if (f >= 10) {
+      e++;
+      f /= 10;
+    }
+  }
+  var digits = "";
+  for (var i = 0; i < n; i++) {
+    var d = Math.floor(f);
+    digits += String.fromCharCode(d + 48);
+    f -= d;
+    f *= 10;
+  }
+  var exp = String(Math.abs(e));
+  while (exp.length < 3) {
+    exp = "0" + exp;
+  }
+  return sign + digits[0] + "." + digits.substr(1) + "e" + (e < 0 ? "-" : "+") + exp;
+};
+var $int64String = function(x, signed) {
+  var high = x.$high, low = x.$low, neg = false;
+  if (signed && high < 0) {
+    neg = true;
+    high = low === 0 ? -high : -high - 1;
+    low = (4294967296 - low) % 4294967296;
+  }
+  var s = "";
+  do {
+    var r = (high % 10) * 4294967296 + low;
+    high = Math.floor(high / 10);
+    low = Math.floor(r / 10);
+    s = String(r % 10) + s;
+  } while (high !== 0 || low !== 0);
+  return neg ? "-" + s : s;
+};
+
 var $panic = function(value) {
   $curGoroutine.panicStack.push(value);
   $callDeferred(null, null, true);
@@ -111,9 +223,67 @@ var $recover = function() {
 };
 var $throw = function(err) { throw err; };
 
+/* $isStackOverflow reports whether err is thrown by the JavaScript engine because the call stack is exhausted. */
+var $isStackOverflow = function(err) {
+  return (err instanceof RangeError && /call stack/.test(err.message)) || (err instanceof Error && err.name === "InternalError" && /recursion/.test(err.message));
+};
+
+var $stackTrace = "full"; /* "full", "go" or "none", set by the program according to the --stacktrace flag */
+var $panicReport = function(err) {
+  if (!(err instanceof Error)) {
+    return err;
+  }
+  if ($isStackOverflow(err)) {
+    return $stackOverflowReport(err);
+  }
+  var message = err.$goPanic ? "panic: " + err.message : String(err);
+  if (err.stack === undefined || $stackTrace === "none") {
+    return message;
+  }
+  if ($stackTrace === "go") {
+    /* Only source-mapped frames refer to Go files. */
+    var frames = err.stack.split("\n").filter(function(line) { return /\.go:\d+/.test(line); });
+    return message + "\n\n" + (frames.length !== 0 ? frames.join("\n") : "(no Go stack frames, they require source maps)");
+  }
+  return err.$goPanic ? message + "\n\n" + err.stack : err.stack;
+};
+
+/* $stackOverflowReport formats a stack overflow like the Go runtime does, followed by the frames at the top
+   of the stack, which show where the recursion is, with repeated frames collapsed. */
+var $stackOverflowReport = function(err) {
+  var message = "runtime: goroutine stack exceeds the JavaScript call stack size\nfatal error: stack overflow";
+  if (err.stack === undefined || $stackTrace === "none") {
+    return message;
+  }
+  var frames = err.stack.split("\n").filter(function(line) { return line !== "" && line !== String(err); });
+  var goFrames = frames.filter(function(line) { return /\.go:\d+/.test(line); });
+  if (goFrames.length !== 0 || $stackTrace === "go") {
+    frames = goFrames;
+  }
+  var lines = [];
+  for (var i = 0; i < frames.length; i++) {
+    var n = 1;
+    while (i + 1 < frames.length && frames[i + 1] === frames[i]) {
+      i++;
+      n++;
+    }
+    lines.push(n === 1 ? frames[i] : frames[i] + " (" + n + " times)");
+  }
+  if (lines.length === 0) {
+    lines.push("(no Go stack frames, they require source maps)");
+  }
+  return message + "\n\n" + lines.join("\n") + "\n\nThe recursion may be too deep for the call stack, whose size gopherjs run and test take from ulimit -s.";
+};
+
 var $noGoroutine = { asleep: false, exit: false, deferStack: [], panicStack: [] };
-var $curGoroutine = $noGoroutine, $totalGoroutines = 0, $awakeGoroutines = 0, $checkForDeadlock = true;
+var $curGoroutine = $noGoroutine, $checkForDeadlock = true;
 var $mainFinished = false;
+`
+
+// scheduler runs goroutines, which programs without go statements or
+// blocking operations don't need, see Features.
+const scheduler = `
+var $totalGoroutines = 0, $awakeGoroutines = 0;
 var $go = function(fun, args, direct) {
   $totalGoroutines++;
   $awakeGoroutines++;
@@ -129,6 +299,12 @@ var $go = function(fun, args, direct) {
       $goroutine.exit = true;
     } catch (err) {
       if (!$goroutine.exit) {
+        if ($global.process !== undefined) {
+          /* An unrecovered panic crashes the whole program, no matter which goroutine it occurred in. */
+          $flushConsole();
+          console.error($panicReport(err));
+          $global.process.exit(2);
+        }
         throw err;
       }
     } finally {
@@ -194,7 +370,10 @@ var $block = function() {
   }
   $curGoroutine.asleep = true;
 };
+`
 
+// channels implements channel operations, see Features.
+const channels = `
 var $send = function(chan, value) {
   if (chan.$closed) {
     $throwRuntimeError("send on closed channel");
@@ -356,3 +535,33 @@ var $select = function(comms) {
   return f;
 };
 `
+
+// race implements the detection of data races of code compiled with race
+// instrumentation, see Features. Accesses of a variable by different
+// goroutines are reported if at least one of them writes it and no
+// synchronizing operation was executed in between. Since goroutines only
+// switch at blocking operations, this finds races involving sleeps, timers
+// and callbacks, but not those hidden by unrelated synchronization.
+const race = `
+var $raceEpoch = 0, $raceGoroutines = 0, $raceAccesses = {}, $raceReported = {};
+var $raceSync = function() {
+  $raceEpoch++;
+};
+var $raceGoroutineID = function(g) {
+  if (g.$raceID === undefined) {
+    g.$raceID = ++$raceGoroutines;
+  }
+  return g.$raceID;
+};
+var $raceAccess = function(name, write) {
+  var g = $curGoroutine, last = $raceAccesses[name];
+  if (last !== undefined && last.goroutine !== g && last.epoch === $raceEpoch && (write || last.write) && !$raceReported[name]) {
+    $raceReported[name] = true;
+    var previous = $raceGoroutineID(last.goroutine), current = $raceGoroutineID(g);
+    console.error("==================\nWARNING: DATA RACE\n" + (write ? "Write" : "Read") + " of " + name + " by goroutine " + current + " after " + (last.write ? "a write" : "a read") + " by goroutine " + previous + " without synchronization in between.\n==================");
+  }
+  $raceAccesses[name] = { goroutine: g, epoch: $raceEpoch, write: write };
+};
+var $raceRead = function(name) { $raceAccess(name, false); };
+var $raceWrite = function(name) { $raceAccess(name, true); };
+`
@@ -0,0 +1,36 @@
+package prelude
+
+// Intrinsics are JavaScript implementations of functions of the standard
+// library, by the full name of the function as returned by types.Func.FullName.
+// The compiler uses them instead of the body of the function, which is often
+// missing because the function is implemented in assembly, or only works on
+// the memory layout of native targets.
+//
+// To add an intrinsic, add a JavaScript expression evaluating to the function.
+// Its arguments and results have the JavaScript representation of their Go
+// types: numbers for integers of up to 32 bits and floats, $Int64 and $Uint64
+// for 64-bit integers, and so on. An intrinsic only takes effect for functions
+// without receiver.
+var Intrinsics = map[string]string{
+	"math.Abs":   `function(x) { return Math.abs(x); }`,
+	"math.Ceil":  `function(x) { return Math.ceil(x); }`,
+	"math.Floor": `function(x) { return Math.floor(x); }`,
+	"math.Sqrt":  `function(x) { return Math.sqrt(x); }`,
+
+	"math.Float32bits": `(function() {
+  var f32 = new Float32Array(1), u32 = new Uint32Array(f32.buffer);
+  return function(f) { f32[0] = f; return u32[0]; };
+})()`,
+	"math.Float32frombits": `(function() {
+  var f32 = new Float32Array(1), u32 = new Uint32Array(f32.buffer);
+  return function(b) { u32[0] = b; return f32[0]; };
+})()`,
+	"math.Float64bits": `(function() {
+  var f64 = new Float64Array(1), u32 = new Uint32Array(f64.buffer);
+  return function(f) { f64[0] = f; return new $Uint64(u32[1], u32[0]); };
+})()`,
+	"math.Float64frombits": `(function() {
+  var f64 = new Float64Array(1), u32 = new Uint32Array(f64.buffer);
+  return function(b) { u32[0] = b.$low; u32[1] = b.$high; return f64[0]; };
+})()`,
+}
@@ -1,7 +1,8 @@
 package prelude
 
+// jsmapping converts values between Go and JavaScript, see Features.
 const jsmapping = `
-var $jsObjectPtr, $jsErrorPtr;
+var $makeFunc = function(fn) { return function() { return $externalize(fn(this, new ($sliceType($jsObjectPtr))($global.Array.prototype.slice.call(arguments, []))), $emptyInterface); }; };
 
 var $needsExternalization = function(t) {
   switch (t.kind) {
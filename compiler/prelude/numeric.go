@@ -161,7 +161,10 @@ var $div64 = function(x, y, returnRemainder) {
   }
   return new x.constructor(high * s, low * s);
 };
+`
 
+// complexDivision implements the division of complex numbers, see Features.
+const complexDivision = `
 var $divComplex = function(n, d) {
   var ninf = n.$real === Infinity || n.$real === -Infinity || n.$imag === Infinity || n.$imag === -Infinity;
   var dinf = d.$real === Infinity || d.$real === -Infinity || d.$imag === Infinity || d.$imag === -Infinity;
@@ -1,6 +1,38 @@
 package prelude
 
-const Prelude = prelude + numeric + types + goroutines + jsmapping
+// Prelude is the complete runtime support code of GopherJS programs.
+const Prelude = Core + complexDivision + scheduler + channels + jsmapping + race
+
+// Core is the part of the prelude that is needed by every program.
+const Core = prelude + numeric + types + goroutines
+
+// A Feature is an optional part of the prelude, which is only needed by
+// programs whose code refers to one of the names it defines.
+type Feature struct {
+	Names []string
+	Code  string
+}
+
+// Defines reports whether name is one of the names defined by f.
+func (f Feature) Defines(name string) bool {
+	for _, n := range f.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Features are the optional parts of the prelude. They must not be referred
+// to by Core. A feature that refers to another one needs it, like the channel
+// operations need the scheduler to block goroutines.
+var Features = []Feature{
+	{Names: []string{"$divComplex"}, Code: complexDivision},
+	{Names: []string{"$go", "$schedule", "$block", "$setTimeout", "$totalGoroutines"}, Code: scheduler},
+	{Names: []string{"$send", "$recv", "$close", "$select"}, Code: channels},
+	{Names: []string{"$makeFunc", "$externalize", "$externalizeFunction", "$internalize", "$needsExternalization", "$isASCII"}, Code: jsmapping},
+	{Names: []string{"$raceSync", "$raceRead", "$raceWrite"}, Code: race},
+}
 
 const prelude = `Error.stackTraceLimit = Infinity;
 
@@ -24,12 +56,13 @@ if (typeof module !== "undefined") {
 }
 
 var $packages = {}, $idCounter = 0;
+var $mainPkg; /* set by the code starting the program */
 var $keys = function(m) { return m ? Object.keys(m) : []; };
 var $flushConsole = function() {};
 var $throwRuntimeError; /* set by package "runtime" */
+var $jsObjectPtr, $jsErrorPtr; /* set by package "runtime" */
 var $throwNilPointerError = function() { $throwRuntimeError("invalid memory address or nil pointer dereference"); };
 var $call = function(fn, rcvr, args) { return fn.apply(rcvr, args); };
-var $makeFunc = function(fn) { return function() { return $externalize(fn(this, new ($sliceType($jsObjectPtr))($global.Array.prototype.slice.call(arguments, []))), $emptyInterface); }; };
 var $unused = function(v) {};
 
 var $mapArray = function(array, f) {
@@ -41,6 +74,9 @@ var $mapArray = function(array, f) {
 };
 
 var $methodVal = function(recv, name) {
+  if (recv === $ifaceNil) {
+    $throwNilPointerError(); /* like in Go, when the method value is evaluated, not called */
+  }
   var vals = recv.$methodVals || {};
   recv.$methodVals = vals; /* noop for primitives */
   var f = vals[name];
@@ -389,6 +425,9 @@ var $equal = function(a, b, type) {
   case $kindStruct:
     for (var i = 0; i < type.fields.length; i++) {
       var f = type.fields[i];
+      if (f.name === "_") {
+        continue;
+      }
       if (!$equal(a[f.prop], b[f.prop], f.typ)) {
         return false;
       }
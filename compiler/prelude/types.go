@@ -246,9 +246,10 @@ var $newType = function(size, kind, string, named, pkg, exported, constructor) {
           typ.comparable = false;
         }
       });
+      var keyFields = fields.filter(function(f) { return f.name !== "_"; });
       typ.keyFor = function(x) {
         var val = x.$val;
-        return $mapArray(fields, function(f) {
+        return $mapArray(keyFields, function(f) {
           return String(f.typ.keyFor(val[f.prop])).replace(/\\/g, "\\\\").replace(/\$/g, "\\$");
         }).join("$");
       };
@@ -0,0 +1,113 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/gopherjs/gopherjs/compiler/astutil"
+)
+
+// Race instrumentation is enabled by the race argument of Compile.
+//
+// Reads and writes of package level variables call $raceRead and $raceWrite
+// with the qualified name of the variable, and statements executing an
+// operation that synchronizes goroutines in the sense of the memory model
+// are preceded by a call of $raceSync. The prelude reports accesses of a
+// variable by two goroutines, one of which writes it, without a call of
+// $raceSync in between. Accesses through pointers are not recorded.
+
+// raceName returns the name under which the accesses of the variable referred
+// to by the identifier or qualified identifier e are recorded, or "" if they
+// are not instrumented.
+func (c *funcContext) raceName(e ast.Expr) string {
+	if !c.p.race {
+		return ""
+	}
+	var obj types.Object
+	switch e := astutil.RemoveParens(e).(type) {
+	case *ast.Ident:
+		obj = c.p.ObjectOf(e)
+	case *ast.SelectorExpr:
+		if _, isSel := c.p.SelectionOf(e); isSel {
+			return ""
+		}
+		obj = c.p.Uses[e.Sel]
+	}
+	v, ok := obj.(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return ""
+	}
+	return strconv.Quote(v.Pkg().Path() + "." + v.Name())
+}
+
+// synchronizes reports whether the statement s, not counting the statements
+// in its body, executes a channel operation, starts a goroutine or calls a
+// function of the packages sync and sync/atomic.
+func (c *funcContext) synchronizes(s ast.Stmt) bool {
+	var nodes []ast.Node
+	switch s := s.(type) {
+	case *ast.GoStmt, *ast.SendStmt, *ast.SelectStmt:
+		return true
+	case *ast.BlockStmt, *ast.LabeledStmt:
+		return false
+	case *ast.IfStmt:
+		nodes = []ast.Node{s.Init, s.Cond}
+	case *ast.ForStmt:
+		nodes = []ast.Node{s.Init, s.Cond}
+	case *ast.RangeStmt:
+		if _, isChan := c.p.TypeOf(s.X).Underlying().(*types.Chan); isChan {
+			return true
+		}
+		nodes = []ast.Node{s.X}
+	case *ast.SwitchStmt:
+		nodes = []ast.Node{s.Init, s.Tag}
+	case *ast.TypeSwitchStmt:
+		nodes = []ast.Node{s.Init, s.Assign}
+	default:
+		nodes = []ast.Node{s}
+	}
+
+	found := false
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		ast.Inspect(n, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.UnaryExpr:
+				found = found || n.Op == token.ARROW
+			case *ast.CallExpr:
+				found = found || c.callSynchronizes(n)
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// callSynchronizes reports whether call is a call of close, or of a function
+// or method of the packages sync and sync/atomic.
+func (c *funcContext) callSynchronizes(call *ast.CallExpr) bool {
+	var obj types.Object
+	switch fun := astutil.RemoveParens(call.Fun).(type) {
+	case *ast.Ident:
+		obj = c.p.Uses[fun]
+	case *ast.SelectorExpr:
+		if sel, ok := c.p.SelectionOf(fun); ok {
+			obj = sel.Obj()
+		} else {
+			obj = c.p.Uses[fun.Sel]
+		}
+	}
+	switch obj := obj.(type) {
+	case *types.Builtin:
+		return obj.Name() == "close"
+	case *types.Func:
+		return obj.Pkg() != nil && (obj.Pkg().Path() == "sync" || obj.Pkg().Path() == "sync/atomic")
+	}
+	return false
+}
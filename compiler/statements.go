@@ -26,6 +26,9 @@ func (c *funcContext) translateStmt(stmt ast.Stmt, label *types.Label) {
 
 	stmt = filter.IncDecStmt(stmt, c.p.Info.Info)
 	stmt = filter.Assign(stmt, c.p.Info.Info, c.p.Info.Pkg)
+	if c.p.race && c.synchronizes(stmt) {
+		c.Printf("$raceSync();")
+	}
 
 	switch s := stmt.(type) {
 	case *ast.BlockStmt:
@@ -164,7 +167,13 @@ func (c *funcContext) translateStmt(stmt ast.Stmt, label *types.Label) {
 
 	case *ast.RangeStmt:
 		refVar := c.newVariable("_ref")
-		c.Printf("%s = %s;", refVar, c.translateExpr(s.X))
+		x := c.translateExpr(s.X)
+		if _, isArray := c.p.TypeOf(s.X).Underlying().(*types.Array); isArray && !isBlank(s.Value) {
+			// The values are those of a copy of the array, which the loop
+			// may modify.
+			x = c.translateImplicitConversionWithCloning(s.X, c.p.TypeOf(s.X))
+		}
+		c.Printf("%s = %s;", refVar, x)
 
 		switch t := c.p.TypeOf(s.X).Underlying().(type) {
 		case *types.Basic:
@@ -362,16 +371,17 @@ func (c *funcContext) translateStmt(stmt ast.Stmt, label *types.Label) {
 			c.Printf("%s", c.translateAssign(lhs, s.Rhs[0], s.Tok == token.DEFINE))
 
 		case len(s.Lhs) > 1 && len(s.Rhs) == 1:
+			lhsList := c.evaluateLhsOperands(s.Lhs)
 			tupleVar := c.newVariable("_tuple")
 			c.Printf("%s = %s;", tupleVar, c.translateExpr(s.Rhs[0]))
 			tuple := c.p.TypeOf(s.Rhs[0]).(*types.Tuple)
-			for i, lhs := range s.Lhs {
-				lhs = astutil.RemoveParens(lhs)
+			for i, lhs := range lhsList {
 				if !isBlank(lhs) {
 					c.Printf("%s", c.translateAssign(lhs, c.newIdent(fmt.Sprintf("%s[%d]", tupleVar, i), tuple.At(i).Type()), s.Tok == token.DEFINE))
 				}
 			}
 		case len(s.Lhs) == len(s.Rhs):
+			lhsList := c.evaluateLhsOperands(s.Lhs)
 			tmpVars := make([]string, len(s.Rhs))
 			for i, rhs := range s.Rhs {
 				tmpVars[i] = c.newVariable("_tmp")
@@ -381,8 +391,7 @@ func (c *funcContext) translateStmt(stmt ast.Stmt, label *types.Label) {
 				}
 				c.Printf("%s", c.translateAssign(c.newIdent(tmpVars[i], c.p.TypeOf(s.Lhs[i])), rhs, true))
 			}
-			for i, lhs := range s.Lhs {
-				lhs = astutil.RemoveParens(lhs)
+			for i, lhs := range lhsList {
 				if !isBlank(lhs) {
 					c.Printf("%s", c.translateAssign(lhs, c.newIdent(tmpVars[i], c.p.TypeOf(lhs)), s.Tok == token.DEFINE))
 				}
@@ -656,11 +665,73 @@ func (c *funcContext) translateLoopingStmt(cond func() string, body *ast.BlockSt
 	c.PrintCond(!flatten, "}", fmt.Sprintf("$s = %d; continue; case %d:", data.beginCase, data.endCase))
 }
 
-func (c *funcContext) translateAssign(lhs, rhs ast.Expr, define bool) string {
+// evaluateLhsOperands evaluates the operands of the index expressions and
+// pointer indirections on the left-hand sides of an assignment with several
+// left-hand sides into temporary variables, as the spec requires this to happen
+// before any of the values is assigned. It returns the left-hand sides with
+// the operands replaced by the temporary variables.
+func (c *funcContext) evaluateLhsOperands(lhsList []ast.Expr) []ast.Expr {
+	operand := func(e ast.Expr) ast.Expr {
+		if c.p.Types[e].Value != nil {
+			return e
+		}
+		tmpVar := c.newVariable("_lhs")
+		c.Printf("%s = %s;", tmpVar, c.translateExpr(e))
+		return c.newIdent(tmpVar, c.p.TypeOf(e))
+	}
+	var evaluate func(lhs ast.Expr) ast.Expr
+	evaluate = func(lhs ast.Expr) ast.Expr {
+		switch l := lhs.(type) {
+		case *ast.ParenExpr:
+			return evaluate(l.X)
+		case *ast.IndexExpr:
+			x := l.X
+			if _, isArray := c.p.TypeOf(l.X).Underlying().(*types.Array); isArray {
+				x = evaluate(l.X) // The array itself is assigned to.
+			} else {
+				x = operand(l.X)
+			}
+			e := &ast.IndexExpr{X: x, Index: operand(l.Index)}
+			c.setType(e, c.p.TypeOf(l))
+			return e
+		case *ast.StarExpr:
+			e := &ast.StarExpr{X: operand(l.X)}
+			c.setType(e, c.p.TypeOf(l))
+			return e
+		case *ast.SelectorExpr:
+			sel, ok := c.p.SelectionOf(l)
+			if !ok {
+				return l // qualified identifier
+			}
+			x := l.X
+			if _, isPointer := c.p.TypeOf(l.X).Underlying().(*types.Pointer); isPointer {
+				x = operand(l.X)
+			} else {
+				x = evaluate(l.X)
+			}
+			e := &ast.SelectorExpr{X: x, Sel: l.Sel}
+			c.setType(e, c.p.TypeOf(l))
+			c.p.additionalSelections[e] = sel
+			return e
+		default:
+			return lhs
+		}
+	}
+	evaluated := make([]ast.Expr, len(lhsList))
+	for i, lhs := range lhsList {
+		evaluated[i] = evaluate(lhs)
+	}
+	return evaluated
+}
+
+func (c *funcContext) translateAssign(lhs, rhs ast.Expr, define bool) (assign string) {
 	lhs = astutil.RemoveParens(lhs)
 	if isBlank(lhs) {
 		panic("translateAssign with blank lhs")
 	}
+	if name := c.raceName(lhs); name != "" {
+		defer func() { assign = "$raceWrite(" + name + "); " + assign }()
+	}
 
 	if l, ok := lhs.(*ast.IndexExpr); ok {
 		if t, ok := c.p.TypeOf(l.X).Underlying().(*types.Map); ok {
@@ -393,6 +393,15 @@ func fieldName(t *types.Struct, i int) string {
 	return name
 }
 
+// methodProp returns the name of the JavaScript property holding the method
+// o in the prototypes of types and in interface method sets.
+func methodProp(o types.Object) string {
+	if reservedKeywords[o.Name()] {
+		return o.Name() + "$"
+	}
+	return o.Name()
+}
+
 func typeKind(ty types.Type) string {
 	switch t := ty.Underlying().(type) {
 	case *types.Basic:
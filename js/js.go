@@ -129,7 +129,7 @@ func Keys(o *Object) []string {
 	return s
 }
 
-// MakeWrapper creates a JavaScript object which has wrappers for the exported methods of i. Use explicit getter and setter methods to expose struct fields to JavaScript.
+// MakeWrapper creates a JavaScript object which has wrappers for the exported methods of i. Use explicit getter and setter methods or MakeFullWrapper to expose struct fields to JavaScript.
 func MakeWrapper(i interface{}) *Object {
 	v := InternalObject(i)
 	o := Global.Get("Object").New()
@@ -147,6 +147,40 @@ func MakeWrapper(i interface{}) *Object {
 	return o
 }
 
+// MakeFullWrapper creates a JavaScript object like MakeWrapper, which additionally has
+// properties for the exported fields of i, if i is a struct or a pointer to a struct.
+// Reading a property converts the value of the field to JavaScript, and assigning to it
+// converts the assigned value back and stores it in the field. Embedded fields
+// are not exposed.
+func MakeFullWrapper(i interface{}) *Object {
+	v := InternalObject(i)
+	o := MakeWrapper(i)
+	constructor := v.Get("constructor")
+	fields := constructor.Get("fields")
+	if elem := constructor.Get("elem"); elem != Undefined {
+		fields = elem.Get("fields")
+	}
+	if fields == Undefined {
+		return o
+	}
+	for i := 0; i < fields.Length(); i++ {
+		f := fields.Index(i)
+		if !f.Get("exported").Bool() || f.Get("anonymous").Bool() {
+			continue
+		}
+		Global.Get("Object").Call("defineProperty", o, f.Get("name").String(), M{
+			"enumerable": true,
+			"get": func() *Object {
+				return Global.Call("$externalize", v.Get("$val").Get(f.Get("prop").String()), f.Get("typ"))
+			},
+			"set": func(value *Object) {
+				v.Get("$val").Set(f.Get("prop").String(), Global.Call("$internalize", value, f.Get("typ")))
+			},
+		})
+	}
+	return o
+}
+
 // NewArrayBuffer creates a JavaScript ArrayBuffer from a byte slice.
 func NewArrayBuffer(b []byte) *Object {
 	slice := InternalObject(b)
@@ -453,6 +453,66 @@ func TestMakeWrapper(t *testing.T) {
 	js.Global.Call("eval", `(function(f, m) { f(m); })`).Invoke(f, js.MakeWrapper(m))
 }
 
+type wrappedStruct struct {
+	Name   string
+	Count  int
+	hidden int
+}
+
+func (w *wrappedStruct) Greeting() string { return "hello, " + w.Name }
+
+func TestMakeFullWrapper(t *testing.T) {
+	w := &wrappedStruct{Name: "gopher", Count: 1, hidden: 3}
+	o := js.MakeFullWrapper(w)
+	if !js.Global.Call("eval", `(function(o) { return o.Name === "gopher" && o.Count === 1 && o.hidden === undefined && o.Greeting() === "hello, gopher"; })`).Invoke(o).Bool() {
+		t.Error("fields or methods are not accessible from JavaScript")
+	}
+
+	js.Global.Call("eval", `(function(o) { o.Name = "world"; o.Count++; })`).Invoke(o)
+	if w.Name != "world" || w.Count != 2 {
+		t.Errorf("got Name %q and Count %d, want %q and 2", w.Name, w.Count, "world")
+	}
+	if o.Interface() != w {
+		t.Fail()
+	}
+}
+
+type panicStringer struct{}
+
+func (panicStringer) String() string { return "stringer" }
+
+type panicStruct struct{ A int }
+
+type panicInt int
+
+func TestPanicMessage(t *testing.T) {
+	catch := js.Global.Call("eval", `(function(f) { try { f(); } catch (e) { return e.message; } })`)
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{"boom", "boom"},
+		{42, "42"},
+		{int64(-1) << 62, "-4611686018427387904"},
+		{^uint64(0), "18446744073709551615"},
+		{4.25, "+4.250000e+000"},
+		{float32(-0.1), "-1.000000e-001"},
+		{1e300, "+1.000000e+300"},
+		{true, "true"},
+		{complex(1, -2), "(+1.000000e+000-2.000000e+000i)"},
+		{fmt.Errorf("error %d", 1), "error 1"},
+		{panicStringer{}, "stringer"},
+		{panicInt(3), "js_test.panicInt(3)"},
+		{panicStruct{1}, "(js_test.panicStruct)"},
+	}
+	for _, tt := range tests {
+		value := tt.value
+		if got := catch.Invoke(func() { panic(value) }).String(); got != tt.want {
+			t.Errorf("panic(%#v): got message %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
 func TestCallWithNull(t *testing.T) {
 	c := make(chan int, 1)
 	js.Global.Set("test", func() {
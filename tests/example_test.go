@@ -0,0 +1,24 @@
+package tests
+
+import "fmt"
+
+// Examples are run by "gopherjs test", which compares their output with the
+// Output comment, with or without the syscall module of Node.js.
+
+func Example() {
+	fmt.Println("hello, gopher")
+	fmt.Printf("%d %q\n", 42, "js")
+	// Output:
+	// hello, gopher
+	// 42 "js"
+}
+
+func Example_unordered() {
+	for _, k := range []string{"c", "a", "b"} {
+		fmt.Println(k)
+	}
+	// Unordered output:
+	// a
+	// b
+	// c
+}
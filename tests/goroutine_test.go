@@ -1,7 +1,10 @@
 package tests
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -140,3 +143,143 @@ func TestDeferWithBlocking(t *testing.T) {
 	fmt.Print("")
 	return
 }
+
+func TestRangeOverChannel(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		for i := 1; i <= 3; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+	var got []int
+	for v := range ch {
+		got = append(got, v)
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("got %v, want [1 2 3]", got)
+	}
+
+	buffered := make(chan string, 2)
+	buffered <- "a"
+	buffered <- "b"
+	close(buffered)
+	n := 0
+	for range buffered {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("got %d values from closed buffered channel, want 2", n)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not canceled")
+	}
+	if ctx.Err() != context.Canceled {
+		t.Errorf("got %v, want %v", ctx.Err(), context.Canceled)
+	}
+}
+
+func TestContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+	select {
+	case <-child.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context did not time out")
+	}
+	if ctx.Err() != context.DeadlineExceeded || child.Err() != context.DeadlineExceeded {
+		t.Errorf("got %v and %v, want %v", ctx.Err(), child.Err(), context.DeadlineExceeded)
+	}
+}
+
+func TestWaitGroup(t *testing.T) {
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			time.Sleep(time.Duration(5-i) * time.Millisecond)
+			results[i] = i * i
+		}(i)
+	}
+	wg.Wait()
+	for i, r := range results {
+		if r != i*i {
+			t.Errorf("results[%d] = %d, want %d", i, r, i*i)
+		}
+	}
+}
+
+func TestMutexBlocks(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		mu.Lock()
+		events = append(events, "locked")
+		mu.Unlock()
+		close(done)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	events = append(events, "unlocking")
+	mu.Unlock()
+	<-done
+	if len(events) != 2 || events[0] != "unlocking" || events[1] != "locked" {
+		t.Errorf("got events %v", events)
+	}
+}
+
+func TestRWMutexAndOnce(t *testing.T) {
+	var rw sync.RWMutex
+	var once sync.Once
+	calls := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			once.Do(func() { calls++ })
+			rw.RLock()
+			time.Sleep(time.Millisecond)
+			rw.RUnlock()
+		}()
+	}
+	wg.Wait()
+	rw.Lock()
+	rw.Unlock()
+	if calls != 1 {
+		t.Errorf("once.Do ran %d times, want 1", calls)
+	}
+}
+
+func TestGosched(t *testing.T) {
+	// The goroutine only runs when the loop yields, since the scheduler is
+	// cooperative.
+	done := false
+	go func() { done = true }()
+	yields := 0
+	for !done {
+		if yields == 100 {
+			t.Fatal("goroutine did not run while yielding")
+		}
+		runtime.Gosched()
+		yields++
+	}
+	if yields == 0 {
+		t.Error("goroutine ran before yielding")
+	}
+}
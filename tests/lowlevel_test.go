@@ -4,10 +4,20 @@ package tests_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 )
 
 // Test for internalization/externalization of time.Time/Date when time package is imported
@@ -29,3 +39,647 @@ func TestTimeInternalizationExternalization(t *testing.T) {
 		t.Fatalf("got != want:\ngot:\n%s\nwant:\n%s", got, want)
 	}
 }
+
+// Test that an unrecovered panic in a goroutine other than the main one
+// terminates the program with a non-zero exit status and reports the panic.
+func TestGoroutinePanic(t *testing.T) {
+	cmd := exec.Command("gopherjs", "run", filepath.Join("testdata", "goroutine_panic.go"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Fatalf("got error %v, want non-zero exit status:\n%s", err, stdout)
+	}
+	if !strings.Contains(stderr.String(), "panic in goroutine") {
+		t.Errorf("panic message missing from stderr:\n%s", stderr.String())
+	}
+	if bytes.Contains(stdout, []byte("not reached")) {
+		t.Errorf("program kept running after goroutine panic:\n%s", stdout)
+	}
+}
+
+// Test that the generated code runs without errors when node treats all code as strict mode code.
+func TestStrictMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-strict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "strict.js")
+
+	if out, err := exec.Command("gopherjs", "build", "--strict", "-o", script, filepath.Join("testdata", "time_inexternalization.go")).CombinedOutput(); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	got, err := exec.Command("node", "--use-strict", script).Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+
+	want, err := ioutil.ReadFile(filepath.Join("testdata", "time_inexternalization.out"))
+	if err != nil {
+		t.Fatalf("error reading .out file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got != want:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// Test that a bundle built for the worker target dispatches messages to exported functions.
+func TestWorkerTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-worker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "worker.js")
+
+	if out, err := exec.Command("gopherjs", "build", "--target=worker", "-o", script, filepath.Join("testdata", "worker.go")).CombinedOutput(); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	got, err := exec.Command("node", filepath.Join("testdata", "worker_harness.js"), script).Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+
+	want, err := ioutil.ReadFile(filepath.Join("testdata", "worker.out"))
+	if err != nil {
+		t.Fatalf("error reading .out file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got != want:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// Test that subcommands print their own usage, listing their flags, with --help.
+func TestSubcommandHelp(t *testing.T) {
+	tests := []struct {
+		cmd   string
+		flags []string
+	}{
+		{"build", []string{"--output", "--tags", "--minify", "--verbose", "--bundle-report"}},
+		{"run", []string{"--browser", "--tags", "--timeout"}},
+		{"test", []string{"--bench", "--run", "--short", "--cover", "--coverprofile"}},
+	}
+	for _, tt := range tests {
+		out, err := exec.Command("gopherjs", tt.cmd, "--help").CombinedOutput()
+		if err != nil {
+			t.Fatalf("gopherjs %s --help: %v:\n%s", tt.cmd, err, out)
+		}
+		if !strings.Contains(string(out), "gopherjs "+tt.cmd) {
+			t.Errorf("gopherjs %s --help: usage line missing:\n%s", tt.cmd, out)
+		}
+		for _, flag := range tt.flags {
+			if !strings.Contains(string(out), flag) {
+				t.Errorf("gopherjs %s --help: flag %s missing:\n%s", tt.cmd, flag, out)
+			}
+		}
+	}
+}
+
+// Test that "gopherjs test --count=0" compiles the tests without running them.
+func TestCountZero(t *testing.T) {
+	out, err := exec.Command("gopherjs", "test", "--count=0", "./testdata/failing").CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "[no tests run]") || strings.Contains(string(out), "the test was run") {
+		t.Errorf("got output:\n%s", out)
+	}
+}
+
+// Test that "gopherjs run --timeout" terminates a program that runs too long
+// and exits with a non-zero status.
+func TestRunTimeout(t *testing.T) {
+	cmd := exec.Command("gopherjs", "run", "--timeout", "2s", filepath.Join("testdata", "sleep.go"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	start := time.Now()
+	err := cmd.Run()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Fatalf("got error %v, want non-zero exit status:\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "timed out after 2s") {
+		t.Errorf("timeout message missing from stderr:\n%s", stderr.String())
+	}
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Errorf("program was terminated after %v", elapsed)
+	}
+}
+
+// Test that programs run with "gopherjs run" can read from the standard input.
+func TestRunStdin(t *testing.T) {
+	cmd := exec.Command("gopherjs", "run", filepath.Join("testdata", "echo.go"))
+	cmd.Stdin = strings.NewReader("hello, gopher\n")
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "echo: hello, gopher\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// Test that programs run with "gopherjs run" can copy from io.Readers to the
+// standard output and error, and scan the standard input.
+func TestRunCopy(t *testing.T) {
+	cmd := exec.Command("gopherjs", "run", filepath.Join("testdata", "copy.go"))
+	cmd.Stdin = strings.NewReader("one two\nthree\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s%s", err, got, stderr.Bytes())
+	}
+	if want := "héllo, gopher\n1: one\n2: two\n3: three\n"; string(got) != want {
+		t.Errorf("got stdout %q, want %q", got, want)
+	}
+	if want := "to stderr\n"; stderr.String() != want {
+		t.Errorf("got stderr %q, want %q", stderr.String(), want)
+	}
+}
+
+// Test that "gopherjs run --race" reports the unsynchronized accesses of a
+// variable, but not those synchronized with a mutex, a WaitGroup or a channel.
+func TestRace(t *testing.T) {
+	cmd := exec.Command("gopherjs", "run", "--race", filepath.Join("testdata", "race.go"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s%s", err, got, stderr.Bytes())
+	}
+	if want := "1\n2 2\n"; string(got) != want {
+		t.Errorf("got stdout %q, want %q", got, want)
+	}
+	if n := strings.Count(stderr.String(), "WARNING: DATA RACE"); n != 1 || !strings.Contains(stderr.String(), "Read of main.racy by goroutine") {
+		t.Errorf("got %d races, want a read of main.racy:\n%s", n, stderr.Bytes())
+	}
+}
+
+// Test that calls inlined by --opt=1 evaluate all of their arguments, like
+// the calls they replace, before the inlined expression can skip an operand
+// or dereference a nil pointer.
+func TestInlineArguments(t *testing.T) {
+	got, err := exec.Command("gopherjs", "run", "--opt=1", filepath.Join("testdata", "inline.go")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "false [a b]\ntrue [a b d]\n"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// Test that "gopherjs run --browser --browser-port" serves the page of the
+// program on the loopback interface until it is interrupted.
+func TestRunBrowserPort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	cmd := exec.Command("gopherjs", "run", "--browser", "--browser-port", strconv.Itoa(port), filepath.Join("testdata", "goos.go"))
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	var page []byte
+	for deadline := time.Now().Add(time.Minute); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		resp, err := http.Get("http://127.0.0.1:" + strconv.Itoa(port) + "/")
+		if err != nil {
+			continue
+		}
+		page, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		break
+	}
+	if !bytes.Contains(page, []byte("<script src=\"goos.go.")) {
+		t.Fatalf("got page %q, want the harness of goos.go:\n%s", page, output.Bytes())
+	}
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("%v:\n%s", err, output.Bytes())
+	}
+	if _, err := http.Get("http://127.0.0.1:" + strconv.Itoa(port) + "/"); err == nil {
+		t.Error("still serving after the interrupt")
+	}
+}
+
+// Test that the arguments after the program of "gopherjs run" are passed to
+// it, and can be parsed with the flag package, even if they look like flags.
+func TestRunFlags(t *testing.T) {
+	got, err := exec.Command("gopherjs", "run", filepath.Join("testdata", "flags.go"), "-name", "gopher", "-n", "2", "extra").Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	// os.Args has the program followed by its five arguments.
+	if want := "hello, gopher\nhello, gopher\n[extra] 6\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// Test that exhausting the call stack is reported as a fatal stack overflow,
+// which runs no deferred calls, with the frames of the recursion.
+func TestStackOverflow(t *testing.T) {
+	cmd := exec.Command("gopherjs", "run", "--quiet", filepath.Join("testdata", "overflow.go"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("got error %v, want non-zero exit status:\n%s%s", err, stdout, stderr.Bytes())
+	}
+	if status := exitErr.Sys().(syscall.WaitStatus).ExitStatus(); status != 2 {
+		t.Errorf("got exit status %d, want 2", status)
+	}
+	if !strings.Contains(stderr.String(), "fatal error: stack overflow") || !strings.Contains(stderr.String(), "recurse") {
+		t.Errorf("stack overflow report missing from stderr:\n%s", stderr.Bytes())
+	}
+	if len(stdout) != 0 {
+		t.Errorf("deferred call ran after the stack overflow:\n%s", stdout)
+	}
+}
+
+// Test that the log package writes timestamped lines to stderr and that
+// log.Fatal exits with status 1 after printing.
+func TestLogFatal(t *testing.T) {
+	cmd := exec.Command("gopherjs", "run", filepath.Join("testdata", "logfatal.go"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("got error %v, want non-zero exit status:\n%s%s", err, stdout, stderr.Bytes())
+	}
+	if status := exitErr.Sys().(syscall.WaitStatus).ExitStatus(); status != 1 {
+		t.Errorf("got exit status %d, want 1", status)
+	}
+	timestamp := `\d{4}/\d\d/\d\d \d\d:\d\d:\d\d `
+	if want := regexp.MustCompile("^" + timestamp + "starting\n" + timestamp + "boom\n$"); !want.Match(stderr.Bytes()) {
+		t.Errorf("got stderr %q, want it to match %s", stderr.Bytes(), want)
+	}
+	if len(stdout) != 0 {
+		t.Errorf("got stdout %q, want none", stdout)
+	}
+}
+
+// Test that "gopherjs run --exec" runs the program with the given command,
+// which receives the script and the arguments of the program.
+func TestRunExec(t *testing.T) {
+	runner := "node " + filepath.Join("testdata", "exec_runner.js")
+	got, err := exec.Command("gopherjs", "run", "--exec", runner, filepath.Join("testdata", "flags.go"), "-name", "gopher", "extra").Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "runner\nhello, gopher\n[extra] 4\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// Test that "gopherjs build --keep-going" builds all packages, reporting the
+// errors of each failing one, while without it the build stops at the first.
+func TestKeepGoing(t *testing.T) {
+	pkgs := []string{"./testdata/keepgoing/a", "./testdata/keepgoing/b", "./testdata/keepgoing/c"}
+	for _, tt := range []struct {
+		flags []string
+		want  []string
+	}{
+		{nil, []string{"undefined: missingA"}},
+		{[]string{"--keep-going"}, []string{"undefined: missingA", "undefined: missingC"}},
+	} {
+		out, err := exec.Command("gopherjs", append(append([]string{"build"}, tt.flags...), pkgs...)...).CombinedOutput()
+		if err == nil {
+			t.Errorf("%v: build succeeded:\n%s", tt.flags, out)
+			continue
+		}
+		if got := strings.Count(string(out), "undefined: "); got != len(tt.want) {
+			t.Errorf("%v: got %d errors, want %d:\n%s", tt.flags, got, len(tt.want), out)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(out), want) {
+				t.Errorf("%v: no error %q:\n%s", tt.flags, want, out)
+			}
+		}
+	}
+}
+
+// Test that a package built with "gopherjs build --library" can be required
+// under Node.js, and its exported functions called.
+func TestLibrary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	lib := filepath.Join(dir, "library.js")
+	if out, err := exec.Command("gopherjs", "build", "--library", "library", "-o", lib, "./testdata/library").CombinedOutput(); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	script := `var lib = require(process.argv[1]);
+console.log(lib.Add(1, 2), lib.Greet("gopher"), JSON.stringify(lib.Fields(" a b  c ")), typeof lib.unexported);`
+	got, err := exec.Command("node", "-e", script, lib).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "3 hello, gopher [\"a\",\"b\",\"c\"] undefined\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// Test that a library built with "gopherjs build --format=cjs" is a directory
+// of CommonJS modules that can be required under Node.js, as a whole or one
+// package at a time.
+func TestCommonJSLibrary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-cjs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	lib := filepath.Join(dir, "library")
+	if out, err := exec.Command("gopherjs", "build", "--format=cjs", "--library", "library", "-o", lib, "./testdata/library").CombinedOutput(); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(lib, "pkg", "strings.js")); err != nil {
+		t.Errorf("no module for the strings package: %v", err)
+	}
+	script := `var lib = require(process.argv[1]);
+console.log(lib.Add(1, 2), lib.Greet("gopher"), JSON.stringify(lib.Fields(" a b  c ")));`
+	got, err := exec.Command("node", "-e", script, lib).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "3 hello, gopher [\"a\",\"b\",\"c\"]\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// The module of a package can be required on its own.
+	got, err = exec.Command("node", "-e", `console.log(require(process.argv[1]).Repeat("go", 3));`, filepath.Join(lib, "pkg", "strings.js")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "gogogo\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// Test that "gopherjs install" puts commands into the directory set by
+// --bin-dir or GOPHERJS_BIN instead of the bin directory of their workspace.
+func TestInstallBinDir(t *testing.T) {
+	gopath, err := ioutil.TempDir("", "gopherjs-bindir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gopath)
+	if err := os.MkdirAll(filepath.Join(gopath, "src", "hello"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(gopath, "src", "hello", "main.go"), []byte("package main\n\nfunc main() { println(\"hello\") }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name  string
+		flags []string
+		env   string
+	}{
+		{name: "flag", flags: []string{"--bin-dir", filepath.Join(gopath, "flag")}},
+		{name: "env", env: "GOPHERJS_BIN=" + filepath.Join(gopath, "env")},
+	} {
+		cmd := exec.Command("gopherjs", append(append([]string{"install", "-q"}, test.flags...), "hello")...)
+		cmd.Env = append(os.Environ(), "GOPATH="+gopath, test.env)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%s: %v:\n%s", test.name, err, out)
+		}
+		if _, err := os.Stat(filepath.Join(gopath, test.name, "hello.js")); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(gopath, "bin", "hello.js")); !os.IsNotExist(err) {
+		t.Errorf("hello.js was installed into the bin directory of the workspace: %v", err)
+	}
+}
+
+// Test that "gopherjs build --entry" builds a non-main package as a program
+// calling the given function after initialization.
+func TestEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-entry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	program := filepath.Join(dir, "entry.js")
+	if out, err := exec.Command("gopherjs", "build", "--entry", "Serve", "-o", program, "./testdata/entry").CombinedOutput(); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	got, err := exec.Command("node", program).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "initialized\nserving\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// Test that --prepend and --append insert files around the generated code,
+// leaving the code before the program out of its strict mode.
+func TestPrependAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-prepend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	prependFile := filepath.Join(dir, "prepend.js")
+	if err := ioutil.WriteFile(prependFile, []byte(`undeclared = "before"; console.log(undeclared)`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	appendFile := filepath.Join(dir, "append.js")
+	if err := ioutil.WriteFile(appendFile, []byte("console.log(\"after\");\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	program := filepath.Join(dir, "goos.js")
+	if out, err := exec.Command("gopherjs", "build", "--prepend", prependFile, "--append", appendFile, "-o", program, filepath.Join("testdata", "goos.go")).CombinedOutput(); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	got, err := exec.Command("node", program).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if !strings.HasPrefix(string(got), "before\n") || !strings.HasSuffix(string(got), " js\nafter\n") {
+		t.Fatalf("got %q, want the program's output between \"before\" and \"after\"", got)
+	}
+}
+
+// Test that --stacktrace selects what is printed for an unrecovered panic.
+func TestStackTrace(t *testing.T) {
+	for _, mode := range []string{"full", "none"} {
+		cmd := exec.Command("gopherjs", "run", "--quiet", "--stacktrace", mode, filepath.Join("testdata", "panic.go"))
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err == nil {
+			t.Fatalf("--stacktrace=%s: got success, want non-zero exit status", mode)
+		}
+		got := strings.TrimSpace(stderr.String())
+		if !strings.HasPrefix(got, "panic: boom") {
+			t.Errorf("--stacktrace=%s: panic message missing:\n%s", mode, got)
+		}
+		if hasStack := strings.Contains(got, "\n    at "); hasStack != (mode == "full") {
+			t.Errorf("--stacktrace=%s: got stack %t, want %t:\n%s", mode, hasStack, mode == "full", got)
+		}
+	}
+}
+
+// Test that "gopherjs build" prints the size and the number of packages of the
+// written command, with the size of each package under -v, unless -q is set.
+func TestBuildSummary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gopherjs-summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "goos.js")
+	build := func(flags ...string) string {
+		cmd := exec.Command("gopherjs", append(append([]string{"build"}, flags...), "-o", output, filepath.Join("testdata", "goos.go"))...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("%v:\n%s", err, stderr.Bytes())
+		}
+		return stderr.String()
+	}
+
+	summary := regexp.MustCompile(`^wrote .*goos\.js \(\d+ (B|KB|MB), \d+ packages\)\n`)
+	if got := build(); !summary.MatchString(got) || strings.Count(got, "\n") != 1 {
+		t.Errorf("got %q, want one line matching %s", got, summary)
+	}
+	if got := build("-v"); !summary.MatchString(got) || !strings.Contains(got, "\n  runtime (") {
+		t.Errorf("-v: got %q, want the summary followed by the size of each package", got)
+	}
+	if got := build("-q"); got != "" {
+		t.Errorf("-q: got %q, want no output", got)
+	}
+}
+
+// Test that --errors=json prints warnings as JSON arrays too, with the
+// severity "warning".
+func TestJSONWarnings(t *testing.T) {
+	cmd := exec.Command("gopherjs", "build", "--errors=json", "--max-func-size=1", "-o", os.DevNull, filepath.Join("testdata", "goos.go"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("%v:\n%s", err, stderr.Bytes())
+	}
+	found := false
+	for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+		if !strings.HasPrefix(line, "[") {
+			continue // the summary of the build
+		}
+		var warnings []struct {
+			File     string
+			Line     int
+			Severity string
+		}
+		if err := json.Unmarshal([]byte(line), &warnings); err != nil {
+			t.Fatalf("%v: %s", err, line)
+		}
+		for _, w := range warnings {
+			if w.Severity != "warning" {
+				t.Errorf("got severity %q, want warning: %s", w.Severity, line)
+			}
+			if filepath.Base(w.File) == "goos.go" && w.Line == 8 {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no warning for main in goos.go:\n%s", stderr.Bytes())
+	}
+}
+
+// Test that --errors=json prints compile errors as a JSON array with their
+// positions.
+func TestJSONErrors(t *testing.T) {
+	cmd := exec.Command("gopherjs", "build", "--errors=json", "-o", os.DevNull, filepath.Join("testdata", "typeerror.go"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("got success, want non-zero exit status")
+	}
+	var errs []struct {
+		File     string
+		Line     int
+		Col      int
+		Message  string
+		Severity string
+	}
+	if err := json.Unmarshal(stderr.Bytes(), &errs); err != nil {
+		t.Fatalf("%v:\n%s", err, stderr.Bytes())
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2:\n%s", len(errs), stderr.Bytes())
+	}
+	for i, line := range []int{6, 7} {
+		e := errs[i]
+		if filepath.Base(e.File) != "typeerror.go" || e.Line != line || e.Col == 0 || e.Message == "" || e.Severity != "error" {
+			t.Errorf("error %d: got %+v, want an error at typeerror.go:%d", i, e, line)
+		}
+	}
+}
+
+// Test that runtime.GOOS and runtime.GOARCH agree with the build context used to
+// select files: GOARCH is "js", and GOOS is the host's, since the standard
+// library's os and syscall packages are built for it.
+func TestGOOSAndGOARCH(t *testing.T) {
+	got, err := exec.Command("gopherjs", "run", filepath.Join("testdata", "goos.go")).Output()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := runtime.GOOS + " js\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// Test that "gopherjs generate" runs directives with the environment of go
+// generate, for the GOOS and GOARCH of the build.
+func TestGenerate(t *testing.T) {
+	got, err := exec.Command("gopherjs", "generate", "./testdata/generate").CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, got)
+	}
+	if want := "gen.go 4 gen " + runtime.GOOS + " js\n"; string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRepl(t *testing.T) {
+	cmd := exec.Command("gopherjs", "repl")
+	cmd.Stdin = strings.NewReader(`x := 20
+func double(n int) int {
+	return 2 * n
+}
+double(x) + 2
+import "strings"
+strings.ToUpper("gopher")
+println("statement")
+undefined
+`)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	for _, want := range []string{"42\n", "GOPHER\n", "statement\n", "undefined: undefined\n"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
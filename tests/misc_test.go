@@ -1,12 +1,20 @@
 package tests
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"math"
+	"math/rand"
 	"reflect"
+	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+	"unsafe"
 	"vendored"
 
 	"github.com/gopherjs/gopherjs/tests/otherpkg"
@@ -80,6 +88,45 @@ func TestStructKey(t *testing.T) {
 	}
 }
 
+func TestArrayKey(t *testing.T) {
+	m := make(map[[3]int]int)
+	m[[3]int{1, 2, 3}] = 42
+	m[[3]int{12, 3}] = 43
+	if m[[3]int{1, 2, 3}] != 42 || m[[3]int{12, 3, 0}] != 43 || len(m) != 2 {
+		t.Fail()
+	}
+
+	m2 := make(map[[2]string]int)
+	m2[[2]string{"a$", "b"}] = 42
+	m2[[2]string{"a", "$b"}] = 43
+	if m2[[2]string{"a$", "b"}] != 42 || m2[[2]string{"a", "$b"}] != 43 || len(m2) != 2 {
+		t.Fail()
+	}
+}
+
+type NestedKey struct {
+	Inner SingleValue
+	Name  string
+	_     int
+}
+
+func TestNestedStructKey(t *testing.T) {
+	m := make(map[NestedKey]int)
+	m[NestedKey{Inner: SingleValue{1}, Name: "a"}] = 42
+	m[NestedKey{Inner: SingleValue{2}, Name: "a"}] = 43
+	m[NestedKey{Inner: SingleValue{1}, Name: "b"}] = 44
+	if m[NestedKey{Inner: SingleValue{1}, Name: "a"}] != 42 || m[NestedKey{Inner: SingleValue{2}, Name: "a"}] != 43 || m[NestedKey{Inner: SingleValue{1}, Name: "b"}] != 44 || len(m) != 3 {
+		t.Fail()
+	}
+
+	m2 := make(map[[2]NestedKey]int)
+	k := [2]NestedKey{{Inner: SingleValue{1}}, {Inner: SingleValue{2}}}
+	m2[k] = 42
+	if m2[[2]NestedKey{{Inner: SingleValue{1}}, {Inner: SingleValue{2}}}] != 42 || reflect.ValueOf(m2).MapIndex(reflect.ValueOf(k)).Interface() != 42 {
+		t.Fail()
+	}
+}
+
 func TestSelectOnNilChan(t *testing.T) {
 	var c1 chan bool
 	c2 := make(chan bool)
@@ -525,6 +572,37 @@ func TestShift(t *testing.T) {
 	}
 }
 
+func TestShiftCounts(t *testing.T) {
+	counts := []uint{0, 31, 32, 40, 64}
+	want32 := []uint32{1, 1 << 31, 0, 0, 0}
+	want64 := []uint64{1, 1 << 31, 1 << 32, 1 << 40, 0}
+	for i, n := range counts {
+		if uint32(1)<<n != want32[i] {
+			t.Errorf("uint32(1) << %d = %d, want %d", n, uint32(1)<<n, want32[i])
+		}
+		if uint64(1)<<n != want64[i] {
+			t.Errorf("uint64(1) << %d = %d, want %d", n, uint64(1)<<n, want64[i])
+		}
+		if int8(-128)>>n != -1 && n != 0 {
+			t.Errorf("int8(-128) >> %d = %d, want -1", n, int8(-128)>>n)
+		}
+		if int64(-1)>>n != -1 {
+			t.Errorf("int64(-1) >> %d = %d, want -1", n, int64(-1)>>n)
+		}
+	}
+
+	x, y := int32(-8), uint32(8)
+	if x>>40 != -1 || x>>32 != -1 || y>>40 != 0 || y<<32 != 0 || x<<31 != 0 {
+		t.Fail()
+	}
+	if z := int8(-128); z>>64 != -1 {
+		t.Fail()
+	}
+	if z := uint64(1); z<<40 != 1099511627776 || z<<64 != 0 {
+		t.Fail()
+	}
+}
+
 func TestTrivialSwitch(t *testing.T) {
 	for {
 		switch {
@@ -586,6 +664,48 @@ func TestDeferNamedTupleReturnImplicitCast(t *testing.T) {
 	}
 }
 
+func TestDeferMutatesNamedResults(t *testing.T) {
+	increment := func() (n int) {
+		defer func() { n++ }()
+		return 5
+	}
+	if n := increment(); n != 6 {
+		t.Errorf("got %d, want 6", n)
+	}
+
+	order := func() (s string) {
+		defer func() { s += "a" }()
+		defer func() { s += "b" }()
+		return "c"
+	}
+	if s := order(); s != "cba" {
+		t.Errorf("got %q, want %q", s, "cba")
+	}
+
+	recovered := func() (n int, msg string) {
+		defer func() {
+			if e := recover(); e != nil {
+				n, msg = -1, e.(string)
+			}
+		}()
+		n = 1
+		panic("fail")
+	}
+	if n, msg := recovered(); n != -1 || msg != "fail" {
+		t.Errorf("got (%d, %q), want (-1, %q)", n, msg, "fail")
+	}
+
+	ch := make(chan int, 1)
+	ch <- 10
+	blocking := func() (n int) {
+		defer func() { n += <-ch }()
+		return 5
+	}
+	if n := blocking(); n != 15 {
+		t.Errorf("got %d, want 15", n)
+	}
+}
+
 func TestSliceOfString(t *testing.T) {
 	defer func() {
 		if err := recover(); err == nil || !strings.Contains(err.(error).Error(), "slice bounds out of range") {
@@ -623,3 +743,1277 @@ func TestTypeConversion(t *testing.T) {
 		t.Fail()
 	}
 }
+
+var forwardA = forwardB + 1
+var forwardB = forwardC() * 2
+
+func forwardC() int { return forwardD }
+
+var forwardD = 20
+
+func TestPackageVarInitOrder(t *testing.T) {
+	if forwardA != 41 || forwardB != 40 || forwardD != 20 {
+		t.Fail()
+	}
+}
+
+func TestUnsafeSizeof(t *testing.T) {
+	type S struct {
+		A byte
+		B int32
+		C [3]int16
+	}
+	var buf [unsafe.Sizeof(int64(0))]byte
+	if unsafe.Sizeof(int32(0)) != 4 || len(buf) != 8 || unsafe.Sizeof(S{}) != 16 {
+		t.Fail()
+	}
+	var s S
+	if unsafe.Alignof(s.B) != 4 || unsafe.Offsetof(s.B) != 4 || unsafe.Offsetof(s.C) != 8 {
+		t.Fail()
+	}
+}
+
+func TestUnsafePointerArithmetic(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil || !strings.Contains(err.(error).Error(), "unsafe.Pointer arithmetic is not supported") {
+			t.Errorf("got panic %v, want a runtime error for unsafe.Pointer arithmetic", err)
+		}
+	}()
+	s := struct{ A, B int32 }{1, 2}
+	p := unsafe.Pointer(uintptr(unsafe.Pointer(&s)) + unsafe.Offsetof(s.B))
+	t.Errorf("got pointer %v, want a panic", p)
+}
+
+var Answer = 0
+
+func Name() string { return "tests" }
+
+func TestSameIdentifierInDifferentPackages(t *testing.T) {
+	if vendored.Answer != 42 || otherpkg.Answer != "otherpkg" || Answer != 0 {
+		t.Fail()
+	}
+	if vendored.Name() != "vendored" || otherpkg.Name() != "otherpkg" || Name() != "tests" {
+		t.Fail()
+	}
+}
+
+func TestMonotonicClock(t *testing.T) {
+	start := time.Now()
+	prev := start
+	for i := 0; i < 1000; i++ {
+		now := time.Now()
+		if now.Sub(prev) < 0 {
+			t.Fatalf("clock went backwards: %v", now.Sub(prev))
+		}
+		prev = now
+	}
+	if time.Since(start) < 0 {
+		t.Fail()
+	}
+}
+
+func BenchmarkMonotonicClock(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		time.Now()
+	}
+}
+
+func TestNilMap(t *testing.T) {
+	var m map[string]int
+	if v := m["a"]; v != 0 {
+		t.Errorf("got %d, want 0", v)
+	}
+	if v, ok := m["a"]; v != 0 || ok {
+		t.Errorf("got (%d, %t), want (0, false)", v, ok)
+	}
+	if len(m) != 0 {
+		t.Fail()
+	}
+	for range m {
+		t.Fail()
+	}
+	delete(m, "a")
+
+	defer func() {
+		err, ok := recover().(runtime.Error)
+		if !ok || err.Error() != "runtime error: assignment to entry in nil map" {
+			t.Errorf("got %v, want %q", err, "runtime error: assignment to entry in nil map")
+		}
+	}()
+	m["a"] = 1
+	t.Fail()
+}
+
+func TestRangeNil(t *testing.T) {
+	var s []int
+	for i, v := range s {
+		t.Errorf("range over nil slice: got iteration (%d, %d)", i, v)
+	}
+	var m map[int]int
+	for k, v := range m {
+		t.Errorf("range over nil map: got iteration (%d, %d)", k, v)
+	}
+	var p *[3]int
+	n := 0
+	for range p {
+		n++
+	}
+	if n != 3 {
+		t.Errorf("range over nil array pointer: got %d iterations, want 3", n)
+	}
+
+	var c chan int
+	done := make(chan bool)
+	go func() {
+		for range c {
+		}
+		done <- true
+	}()
+	select {
+	case <-done:
+		t.Error("range over nil channel: got end of loop, want blocking forever")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+type embeddedReader interface {
+	Read() string
+}
+
+type embeddedWriter interface {
+	Write(s string)
+}
+
+type embeddedReadWriter interface {
+	embeddedReader
+	embeddedWriter
+}
+
+type readOnly struct{ s string }
+
+func (r *readOnly) Read() string { return r.s }
+
+type readWrite struct{ readOnly }
+
+func (rw *readWrite) Write(s string) { rw.s = s }
+
+func TestEmbeddedInterfaceMethodSet(t *testing.T) {
+	var r embeddedReader = &readWrite{}
+	rw, ok := r.(embeddedReadWriter)
+	if !ok {
+		t.Fatal("readWrite does not satisfy embeddedReadWriter")
+	}
+	rw.Write("hello")
+	if rw.Read() != "hello" {
+		t.Fail()
+	}
+
+	r = &readOnly{}
+	if _, ok := r.(embeddedReadWriter); ok {
+		t.Error("readOnly satisfies embeddedReadWriter")
+	}
+	if _, ok := r.(embeddedWriter); ok {
+		t.Error("readOnly satisfies embeddedWriter")
+	}
+}
+
+// TestRandDeterministic checks that math/rand produces the same sequences as
+// native Go for a fixed seed.
+func TestRandDeterministic(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	want := []int{81, 87, 47, 59, 81, 18, 25, 40, 56, 0}
+	for i, w := range want {
+		if got := r.Intn(100); got != w {
+			t.Errorf("Intn #%d: got %d, want %d", i, got, w)
+		}
+	}
+
+	r = rand.New(rand.NewSource(42))
+	if got := r.Int63(); got != 3440579354231278675 {
+		t.Errorf("Int63: got %d", got)
+	}
+	if got := r.Uint32(); got != 283469975 {
+		t.Errorf("Uint32: got %d", got)
+	}
+	if got := r.Float64(); got != 0.604093851558642 {
+		t.Errorf("Float64: got %v", got)
+	}
+
+	rand.Seed(1)
+	if a, b := rand.Intn(100), rand.Intn(100); a != 81 || b != 87 {
+		t.Errorf("Seed(1): got %d, %d, want 81, 87", a, b)
+	}
+}
+
+func TestIntegerDivision(t *testing.T) {
+	a, b := -7, 2
+	if a/b != -3 || a%b != -1 {
+		t.Errorf("int: got %d and %d, want -3 and -1", a/b, a%b)
+	}
+	var a8, b8 int8 = -128, -1
+	if a8/b8 != -128 || a8%b8 != 0 {
+		t.Errorf("int8: got %d and %d, want -128 and 0", a8/b8, a8%b8)
+	}
+	var a64, b64 int64 = -7, 2
+	if a64/b64 != -3 || a64%b64 != -1 {
+		t.Errorf("int64: got %d and %d, want -3 and -1", a64/b64, a64%b64)
+	}
+	var au, bu uint32 = 1<<32 - 1, 2
+	if au/bu != 1<<31-1 || au%bu != 1 {
+		t.Errorf("uint32: got %d and %d", au/bu, au%bu)
+	}
+
+	zero := 0.0
+	if f := 1 / zero; !math.IsInf(f, 1) {
+		t.Errorf("1/0.0: got %v, want +Inf", f)
+	}
+	if f := -1 / zero; !math.IsInf(f, -1) {
+		t.Errorf("-1/0.0: got %v, want -Inf", f)
+	}
+	if f := zero / zero; !math.IsNaN(f) {
+		t.Errorf("0/0.0: got %v, want NaN", f)
+	}
+}
+
+func TestIntegerDivideByZero(t *testing.T) {
+	divide := func(f func()) (msg string) {
+		defer func() {
+			if err, ok := recover().(runtime.Error); ok {
+				msg = err.Error()
+			}
+		}()
+		f()
+		return ""
+	}
+	var zero int
+	var zero64 int64
+	var zeroU uint8
+	tests := []func(){
+		func() { _ = 1 / zero },
+		func() { _ = 1 % zero },
+		func() { _ = 1 / zero64 },
+		func() { _ = 1 % zero64 },
+		func() { _ = 1 / zeroU },
+	}
+	for i, f := range tests {
+		if msg := divide(f); msg != "runtime error: integer divide by zero" {
+			t.Errorf("#%d: got %q, want %q", i, msg, "runtime error: integer divide by zero")
+		}
+	}
+}
+
+type zeroInner struct {
+	N   int64
+	Arr [2]uint8
+}
+
+type zeroOuter struct {
+	B     bool
+	S     string
+	F     float32
+	C     complex128
+	P     *int
+	Sl    []int
+	M     map[string]int
+	Ch    chan int
+	Fn    func()
+	I     interface{}
+	E     error
+	Inner zeroInner
+	Arr   [3]zeroInner
+}
+
+var zeroOuterPkg zeroOuter
+
+func TestZeroValues(t *testing.T) {
+	var (
+		b   bool
+		i   int
+		i8  int8
+		i64 int64
+		u   uint
+		u64 uint64
+		up  uintptr
+		f32 float32
+		f64 float64
+		c64 complex64
+		c   complex128
+		s   string
+		p   *int
+		sl  []int
+		m   map[string]int
+		ch  chan int
+		fn  func()
+		ifc interface{}
+		err error
+		ptr unsafe.Pointer
+		arr [4]int
+		st  zeroOuter
+	)
+	if b || i != 0 || i8 != 0 || i64 != 0 || u != 0 || u64 != 0 || up != 0 || f32 != 0 || f64 != 0 || c64 != 0 || c != 0 || s != "" {
+		t.Errorf("basic zero values: %v %v %v %v %v %v %v %v %v %v %v %q", b, i, i8, i64, u, u64, up, f32, f64, c64, c, s)
+	}
+	if p != nil || sl != nil || m != nil || ch != nil || fn != nil || ifc != nil || err != nil || ptr != nil {
+		t.Error("nilable zero values are not nil")
+	}
+	if len(sl) != 0 || len(m) != 0 || m["x"] != 0 || len(ch) != 0 {
+		t.Error("operations on nil values")
+	}
+	if arr != [4]int{} {
+		t.Errorf("got %v, want zero array", arr)
+	}
+
+	want := zeroOuter{}
+	for _, got := range []zeroOuter{st, zeroOuterPkg, *new(zeroOuter), {S: ""}} {
+		if got.B || got.S != "" || got.F != 0 || got.C != 0 || got.P != nil || got.Sl != nil || got.M != nil || got.Ch != nil || got.Fn != nil || got.I != nil || got.E != nil {
+			t.Errorf("got %+v, want zero struct", got)
+		}
+		if got.Inner != want.Inner || got.Arr != want.Arr {
+			t.Errorf("got %+v and %+v, want zero nested values", got.Inner, got.Arr)
+		}
+	}
+
+	// Zero values must not share storage.
+	var st2 zeroOuter
+	st.Arr[1].Arr[0] = 1
+	st.Inner.N = 1
+	if st2.Arr[1].Arr[0] != 0 || st2.Inner.N != 0 || zeroOuterPkg.Arr[1].Arr[0] != 0 {
+		t.Error("zero values share storage")
+	}
+	var grid [2][2]int
+	grid[0][1] = 1
+	if grid[1][1] != 0 {
+		t.Error("zero array elements share storage")
+	}
+}
+
+const bufferWrites = 100000
+
+func TestBufferWriteString(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < bufferWrites; i++ {
+		buf.WriteString("gopher")
+	}
+	s := buf.String()
+	if len(s) != 6*bufferWrites || s[6*(bufferWrites-1):] != "gopher" {
+		t.Errorf("got string of length %d, want %d", len(s), 6*bufferWrites)
+	}
+}
+
+// BenchmarkBufferWriteString and BenchmarkStringConcatenation compare a
+// bytes.Buffer, whose slice grows by doubling, with appending to a string.
+func BenchmarkBufferWriteString(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		for j := 0; j < bufferWrites; j++ {
+			buf.WriteString("gopher")
+		}
+		_ = buf.String()
+	}
+}
+
+func BenchmarkStringConcatenation(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var s string
+		for j := 0; j < bufferWrites; j++ {
+			s += "gopher"
+		}
+		_ = s
+	}
+}
+
+type jsonAddress struct {
+	Street string `json:"street"`
+	Zip    string `json:"zip,omitempty"`
+}
+
+type jsonPerson struct {
+	Name     string            `json:"name"`
+	Age      int               `json:"age,string"`
+	Email    string            `json:"email,omitempty"`
+	Password string            `json:"-"`
+	Address  *jsonAddress      `json:"address"`
+	Previous []jsonAddress     `json:"previous,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Untagged bool
+	private  int
+}
+
+func TestJSONStructTags(t *testing.T) {
+	p := jsonPerson{
+		Name:     "Gopher",
+		Age:      9,
+		Password: "secret",
+		Address:  &jsonAddress{Street: "Main St"},
+		Previous: []jsonAddress{{Street: "Old St", Zip: "12345"}},
+		Labels:   map[string]string{"b": "2", "a": "1"},
+		Untagged: true,
+		private:  1,
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"Gopher","age":"9","address":{"street":"Main St"},"previous":[{"street":"Old St","zip":"12345"}],"labels":{"a":"1","b":"2"},"Untagged":true}`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var got jsonPerson
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	p.Password, p.private = "", 0
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("round trip: got %+v, want %+v", got, p)
+	}
+}
+
+func TestIntegerToStringConversion(t *testing.T) {
+	if s := string(65); s != "A" {
+		t.Errorf("string(65): got %q, want %q", s, "A")
+	}
+	if s := string(0x4e16); s != "世" || len(s) != 3 {
+		t.Errorf("string(0x4e16): got %q, want %q", s, "世")
+	}
+
+	i, r, u8, u32 := 65, rune(0x4e16), uint8(0xe9), uint32(0x1f600)
+	i64, u64 := int64(0x10000), uint64(0x7e)
+	tests := []struct {
+		got, want string
+	}{
+		{string(i), "A"},
+		{string(r), "世"},
+		{string(u8), "é"},
+		{string(u32), "\U0001f600"},
+		{string(i64), "\U00010000"},
+		{string(u64), "~"},
+		{string(-i), "�"},
+		{string(r + 0x10ffff), "�"},
+		{string(rune(0xd800) + r - r), "�"},
+		{string(i64 << 32), "�"},
+		{string(i64<<32 + 65), "�"},
+		{string(-i64), "�"},
+		{string(u64 << 40), "�"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%d: got %q, want %q", i, tt.got, tt.want)
+		}
+	}
+}
+
+type accessorPoint struct{ x, y int }
+
+func (p *accessorPoint) X() int { return p.x }
+
+// BenchmarkAccessorCall measures calls of a trivial accessor, which are inlined
+// when compiling with --opt=1.
+func BenchmarkAccessorCall(b *testing.B) {
+	p := &accessorPoint{x: 1}
+	sum := 0
+	for i := 0; i < b.N; i++ {
+		sum += p.X()
+	}
+	if sum != b.N {
+		b.Fatalf("got sum %d, want %d", sum, b.N)
+	}
+}
+
+func TestMultipleAssignment(t *testing.T) {
+	a, b := 1, 2
+	a, b = b, a
+	if a != 2 || b != 1 {
+		t.Errorf("swap: got (%d, %d), want (2, 1)", a, b)
+	}
+
+	x, y := 0, 1
+	for i := 0; i < 10; i++ {
+		x, y = y, x+y
+	}
+	if x != 55 {
+		t.Errorf("fibonacci: got %d, want 55", x)
+	}
+
+	s := []int{1, 2, 3}
+	s[0], s[2] = s[2], s[0]
+	if s[0] != 3 || s[2] != 1 {
+		t.Errorf("swap of slice elements: got %v, want [3 2 1]", s)
+	}
+
+	// The index operands on the left are evaluated before the assignments.
+	i := 0
+	i, s[i] = 2, 9
+	if i != 2 || s[0] != 9 || s[2] != 1 {
+		t.Errorf("i, s[i] = 2, 9: got i = %d, s = %v, want i = 2, s = [9 2 1]", i, s)
+	}
+	pair := func() (int, int) { return 1, 7 }
+	i, s[i] = pair()
+	if i != 1 || s[2] != 7 || s[1] != 2 {
+		t.Errorf("i, s[i] = pair(): got i = %d, s = %v, want i = 1, s = [9 2 7]", i, s)
+	}
+	arr := [2][2]int{}
+	i, arr[i][i] = 0, 5
+	if arr[1][1] != 5 || arr[0][0] != 0 {
+		t.Errorf("i, arr[i][i] = 0, 5: got arr = %v, want [[0 0] [0 5]]", arr)
+	}
+
+	v, w := 1, 2
+	p := &v
+	p, *p = &w, 3
+	if v != 3 || w != 2 || p != &w {
+		t.Errorf("p, *p = &w, 3: got v = %d, w = %d, want v = 3, w = 2", v, w)
+	}
+	type point struct{ x, y int }
+	q, r := &point{}, &point{}
+	q, q.x = r, 4
+	if q != r || r.x != 0 {
+		t.Errorf("q, q.x = r, 4: assigned to the field of the new q")
+	}
+
+	m := map[string]int{"a": 1}
+	n, ok := 0, false
+	n, ok = m["a"]
+	if n != 1 || !ok {
+		t.Errorf("comma-ok: got (%d, %t), want (1, true)", n, ok)
+	}
+}
+
+func TestMathIntrinsics(t *testing.T) {
+	if got := math.Float64bits(1); got != 0x3ff0000000000000 {
+		t.Errorf("Float64bits(1): got %#x, want 0x3ff0000000000000", got)
+	}
+	if got := math.Float64bits(math.Copysign(0, -1)); got != 1<<63 {
+		t.Errorf("Float64bits(-0): got %#x, want %#x", got, uint64(1<<63))
+	}
+	if got := math.Float64frombits(0x400921fb54442d18); got != math.Pi {
+		t.Errorf("Float64frombits(0x400921fb54442d18): got %v, want %v", got, math.Pi)
+	}
+	if got := math.Float32bits(-1); got != 0xbf800000 {
+		t.Errorf("Float32bits(-1): got %#x, want 0xbf800000", got)
+	}
+	if got := math.Float32frombits(0x3f800000); got != 1 {
+		t.Errorf("Float32frombits(0x3f800000): got %v, want 1", got)
+	}
+	if got := math.Sqrt(2); got != 1.4142135623730951 {
+		t.Errorf("Sqrt(2): got %v, want 1.4142135623730951", got)
+	}
+	if got := math.Floor(-1.5); got != -2 {
+		t.Errorf("Floor(-1.5): got %v, want -2", got)
+	}
+	if got := math.Ceil(-1.5); got != -1 {
+		t.Errorf("Ceil(-1.5): got %v, want -1", got)
+	}
+	if got := math.Abs(math.Inf(-1)); !math.IsInf(got, 1) {
+		t.Errorf("Abs(-Inf): got %v, want +Inf", got)
+	}
+	if got := math.Abs(math.Copysign(0, -1)); math.Signbit(got) {
+		t.Errorf("Abs(-0): got %v, want 0", got)
+	}
+}
+
+// The errors.Is, errors.As and %w of Go 1.13 are not available with Go 1.9,
+// but their implementation relies on what is tested here: method dispatch
+// and type assertions to interfaces along a chain of Unwrap methods.
+
+type wrappedError struct {
+	msg string
+	err error
+}
+
+func (e *wrappedError) Error() string { return e.msg + ": " + e.err.Error() }
+
+func (e *wrappedError) Unwrap() error { return e.err }
+
+type pathError struct {
+	path string
+}
+
+func (e *pathError) Error() string { return "bad path " + e.path }
+
+func TestErrorWrapping(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	is := func(err, target error) bool {
+		for err != nil {
+			if err == target {
+				return true
+			}
+			u, ok := err.(interface {
+				Unwrap() error
+			})
+			if !ok {
+				return false
+			}
+			err = u.Unwrap()
+		}
+		return false
+	}
+	asPathError := func(err error) (*pathError, bool) {
+		for err != nil {
+			if pe, ok := err.(*pathError); ok {
+				return pe, true
+			}
+			u, ok := err.(interface {
+				Unwrap() error
+			})
+			if !ok {
+				return nil, false
+			}
+			err = u.Unwrap()
+		}
+		return nil, false
+	}
+
+	wrapped := &wrappedError{"outer", &wrappedError{"inner", sentinel}}
+	if got, want := wrapped.Error(), "outer: inner: sentinel"; got != want {
+		t.Errorf("got message %q, want %q", got, want)
+	}
+	if !is(wrapped, sentinel) {
+		t.Error("sentinel not found in chain")
+	}
+	if is(wrapped, errors.New("sentinel")) {
+		t.Error("distinct error with the same message found in chain")
+	}
+	var err error = &wrappedError{"open", &pathError{"/tmp/x"}}
+	if pe, ok := asPathError(err); !ok || pe.path != "/tmp/x" {
+		t.Errorf("got (%v, %t), want the *pathError", pe, ok)
+	}
+	if _, ok := asPathError(wrapped); ok {
+		t.Error("*pathError found in chain without one")
+	}
+}
+
+func TestCap(t *testing.T) {
+	var nilSlice []int
+	if c := cap(nilSlice); c != 0 {
+		t.Errorf("cap of nil slice: got %d, want 0", c)
+	}
+
+	s := make([]int, 0, 2)
+	for i := 0; i < 5; i++ {
+		s = append(s, i)
+		if cap(s) < len(s) {
+			t.Fatalf("after append: got cap %d < len %d", cap(s), len(s))
+		}
+	}
+	grown := cap(s)
+	if grown < 5 {
+		t.Errorf("cap of grown slice: got %d, want at least 5", grown)
+	}
+	if c := cap(s[1:2]); c != grown-1 {
+		t.Errorf("cap of s[1:2]: got %d, want %d", c, grown-1)
+	}
+	if c := cap(s[2:3:4]); c != 2 {
+		t.Errorf("cap of s[2:3:4]: got %d, want 2", c)
+	}
+	if c := cap(s[:0]); c != grown {
+		t.Errorf("cap of s[:0]: got %d, want %d", c, grown)
+	}
+
+	var arr [7]int
+	if c := cap(arr); c != 7 {
+		t.Errorf("cap of array: got %d, want 7", c)
+	}
+	if c := cap(arr[3:]); c != 4 {
+		t.Errorf("cap of arr[3:]: got %d, want 4", c)
+	}
+	var p *[4]int
+	if c := cap(p); c != 4 {
+		t.Errorf("cap of nil array pointer: got %d, want 4", c)
+	}
+
+	ch := make(chan int, 3)
+	ch <- 1
+	if c := cap(ch); c != 3 {
+		t.Errorf("cap of buffered channel: got %d, want 3", c)
+	}
+	var nilChan chan int
+	if c := cap(nilChan); c != 0 {
+		t.Errorf("cap of nil channel: got %d, want 0", c)
+	}
+	if c := cap(make(chan int)); c != 0 {
+		t.Errorf("cap of unbuffered channel: got %d, want 0", c)
+	}
+}
+
+func TestPointerMutation(t *testing.T) {
+	type inner struct{ N int }
+	type outer struct {
+		X  int
+		In inner
+		A  [2]int
+	}
+
+	var s outer
+	p := &s
+	p.X = 5
+	if s.X != 5 {
+		t.Errorf("p.X = 5: got s.X = %d", s.X)
+	}
+	*p = outer{X: 6}
+	if s.X != 6 {
+		t.Errorf("*p = outer{X: 6}: got s.X = %d", s.X)
+	}
+
+	px := &s.X
+	*px = 7
+	if s.X != 7 || p.X != 7 {
+		t.Errorf("*px = 7: got s.X = %d, p.X = %d", s.X, p.X)
+	}
+	pn := &p.In.N
+	*pn++
+	if s.In.N != 1 {
+		t.Errorf("*pn++: got s.In.N = %d", s.In.N)
+	}
+	pin := &s.In
+	pin.N = 8
+	if *pn != 8 {
+		t.Errorf("pin.N = 8: got *pn = %d", *pn)
+	}
+	pa := &s.A[1]
+	*pa = 9
+	if s.A[1] != 9 {
+		t.Errorf("*pa = 9: got s.A[1] = %d", s.A[1])
+	}
+
+	// Copies do not alias.
+	c := s
+	c.X = 10
+	c.In.N = 11
+	if s.X != 7 || *pn != 8 {
+		t.Errorf("mutating a copy changed the original: %+v", s)
+	}
+
+	// Neither do struct values stored in a slice.
+	items := []outer{s}
+	items[0].X = 12
+	if s.X != 7 {
+		t.Errorf("items[0].X = 12: got s.X = %d", s.X)
+	}
+	pi := &items[0]
+	pi.X = 13
+	if items[0].X != 13 {
+		t.Errorf("pi.X = 13: got items[0].X = %d", items[0].X)
+	}
+}
+
+func TestBreakContinueInSwitchAndSelect(t *testing.T) {
+	var got []int
+	for i := 0; i < 4; i++ {
+		switch i {
+		case 1:
+			break // breaks the switch, the loop goes on
+		case 2:
+			continue // continues the loop, skipping the append
+		}
+		got = append(got, i)
+	}
+	if !reflect.DeepEqual(got, []int{0, 1, 3}) {
+		t.Errorf("break and continue in switch: got %v, want [0 1 3]", got)
+	}
+
+	got = nil
+loop:
+	for i := 0; i < 4; i++ {
+		switch {
+		case i == 2:
+			break loop
+		}
+		got = append(got, i)
+	}
+	if !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("labeled break in switch: got %v, want [0 1]", got)
+	}
+
+	got = nil
+	var x interface{} = 0
+	for i := 0; i < 3; i++ {
+		switch x.(type) {
+		case int:
+			if i == 1 {
+				break
+			}
+			got = append(got, i)
+		}
+		got = append(got, -i)
+	}
+	if !reflect.DeepEqual(got, []int{0, 0, -1, 2, -2}) {
+		t.Errorf("break in type switch: got %v, want [0 0 -1 2 -2]", got)
+	}
+
+	// With blocking receives, the loop is compiled to a resumable state
+	// machine, which has its own translation of break and continue.
+	ch := make(chan int)
+	go func() {
+		for i := 0; i < 5; i++ {
+			ch <- i
+		}
+	}()
+	got = nil
+	n := 0
+outer:
+	for {
+		select {
+		case v := <-ch:
+			n++
+			if v == 1 {
+				break
+			}
+			if v == 2 {
+				continue
+			}
+			if v == 4 {
+				break outer
+			}
+			got = append(got, v)
+		}
+		got = append(got, -1)
+	}
+	if n != 5 || !reflect.DeepEqual(got, []int{0, -1, -1, 3, -1}) {
+		t.Errorf("break and continue in select: got %v after %d receives, want [0 -1 -1 3 -1] after 5", got, n)
+	}
+}
+
+func TestNumericConversions(t *testing.T) {
+	// Variables, since constant conversions are checked by the type checker.
+	f, nf, half := 2.9, -2.9, -2.5
+	i, neg := 200, -1
+	big := int64(1)<<53 + 1
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"int(2.9)", int(f), 2},
+		{"int(-2.9)", int(nf), -2},
+		{"int32(-2.5)", int32(half), int32(-2)},
+		{"int64(-2.9)", int64(nf), int64(-2)},
+		{"uint8(2.9)", uint8(f), uint8(2)},
+		{"uint32(3e9)", uint32(3e9 + f - f), uint32(3000000000)},
+		{"int64(-1e10)", int64(-1e10 + f - f), int64(-10000000000)},
+		{"uint64(1<<63)", uint64(float64(1<<63) + f - f), uint64(1 << 63)},
+		{"float64(200)", float64(i), 200.0},
+		{"float64(1<<53+1)", float64(big), float64(1 << 53)},
+		{"float32(0.1)", float64(float32(0.1 + f - f)), 0.10000000149011612},
+		{"int8(200)", int8(i), int8(-56)},
+		{"uint8(-1)", uint8(neg), uint8(255)},
+		{"byte(300)", byte(i + 100), byte(44)},
+		{"int16(70000)", int16(i * 350), int16(4464)},
+		{"uint16(-1)", uint16(neg), uint16(65535)},
+		{"int32(1<<31)", int32(big >> 22), int32(-1 << 31)},
+		{"uint32(-1)", uint32(neg), uint32(1<<32 - 1)},
+		{"int64(-1)", int64(neg), int64(-1)},
+		{"uint64(-1)", uint64(neg), uint64(1<<64 - 1)},
+		{"int64(uint64(1<<64-1))", int64(uint64(neg)), int64(-1)},
+		{"int8(int64(-129))", int8(int64(neg) - 128), int8(127)},
+		{"int(int8(-1))", int(int8(neg)), -1},
+		{"uint32(uint8(255))", uint32(uint8(neg)), uint32(255)},
+		{"int32(uint32(1<<32-1))", int32(uint32(neg)), int32(-1)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+type byAge []testPerson
+
+func (a byAge) Len() int           { return len(a) }
+func (a byAge) Less(i, j int) bool { return a[i].Age < a[j].Age }
+func (a byAge) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type testPerson struct {
+	Name string
+	Age  int
+}
+
+func TestSort(t *testing.T) {
+	people := []testPerson{{"Eve", 40}, {"Ann", 30}, {"Bob", 25}, {"Dan", 30}, {"Cid", 25}}
+	names := func(people []testPerson) string {
+		var s []string
+		for _, p := range people {
+			s = append(s, p.Name)
+		}
+		return strings.Join(s, " ")
+	}
+
+	byName := append([]testPerson(nil), people...)
+	sort.Slice(byName, func(i, j int) bool { return byName[i].Name < byName[j].Name })
+	if got := names(byName); got != "Ann Bob Cid Dan Eve" {
+		t.Errorf("sort.Slice by name: got %s", got)
+	}
+
+	// Equal keys keep their order, also with more elements than insertion
+	// sort handles in one go.
+	stable := append([]testPerson(nil), people...)
+	sort.Stable(byAge(stable))
+	if got := names(stable); got != "Bob Cid Ann Dan Eve" {
+		t.Errorf("sort.Stable by age: got %s", got)
+	}
+	many := make([]testPerson, 100)
+	for i := range many {
+		many[i] = testPerson{Name: strconv.Itoa(i), Age: (i * 7) % 5}
+	}
+	sort.SliceStable(many, func(i, j int) bool { return many[i].Age < many[j].Age })
+	for i := 1; i < len(many); i++ {
+		a, b := many[i-1], many[i]
+		ai, _ := strconv.Atoi(a.Name)
+		bi, _ := strconv.Atoi(b.Name)
+		if a.Age > b.Age || a.Age == b.Age && ai > bi {
+			t.Fatalf("sort.SliceStable: %v before %v", a, b)
+		}
+	}
+
+	sorted := append([]testPerson(nil), people...)
+	sort.Sort(sort.Reverse(byAge(sorted)))
+	if !sort.IsSorted(sort.Reverse(byAge(sorted))) || sorted[0].Name != "Eve" {
+		t.Errorf("sort.Sort reversed: got %s", names(sorted))
+	}
+
+	ints := []int{5, 2, 8, 1}
+	sort.Ints(ints)
+	if i := sort.SearchInts(ints, 5); !reflect.DeepEqual(ints, []int{1, 2, 5, 8}) || i != 2 {
+		t.Errorf("sort.Ints: got %v, and 5 at %d", ints, i)
+	}
+}
+
+func TestNestedSlices(t *testing.T) {
+	grid := make([][]int, 3)
+	for i := range grid {
+		grid[i] = make([]int, 4)
+	}
+	grid[1][2] = 7
+	for i, row := range grid {
+		for j, v := range row {
+			want := 0
+			if i == 1 && j == 2 {
+				want = 7
+			}
+			if v != want {
+				t.Errorf("grid[%d][%d] = %d after setting grid[1][2]", i, j, v)
+			}
+		}
+	}
+
+	// Rows sliced from one backing array alias it, like in Go.
+	backing := make([]int, 6)
+	shared := [][]int{backing[0:3:3], backing[3:6]}
+	shared[1][0] = 5
+	if backing[3] != 5 || shared[0][2] != 0 {
+		t.Errorf("shared rows: got backing %v", backing)
+	}
+	// Appending to a full row reallocates it, leaving the next row alone.
+	shared[0] = append(shared[0], 9)
+	if shared[1][0] != 5 || len(shared[0]) != 4 {
+		t.Errorf("append to full row: got rows %v", shared)
+	}
+
+	// Copying the outer slice copies the row headers, not the rows.
+	copied := make([][]int, len(grid))
+	copy(copied, grid)
+	copied[0][0] = 1
+	copied[2] = []int{8}
+	if grid[0][0] != 1 || len(grid[2]) != 4 {
+		t.Errorf("copied outer slice: got grid %v", grid)
+	}
+
+	// Arrays of arrays are values.
+	var a [2][2]int
+	b := a
+	b[0][1] = 3
+	rows := a[:]
+	rows[1][0] = 4
+	if a[0][1] != 0 || a[1][0] != 4 || b[1][0] != 0 {
+		t.Errorf("arrays of arrays: got a %v, b %v", a, b)
+	}
+
+	jagged := [][]string{{"a"}, {}, {"b", "c"}}
+	jagged = append(jagged[:1], jagged[2:]...)
+	if len(jagged) != 2 || len(jagged[1]) != 2 || jagged[1][1] != "c" {
+		t.Errorf("removing a row: got %v", jagged)
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	// The expected results are those of native Go, which differ from what
+	// JavaScript's RegExp would give for some of them.
+	re := regexp.MustCompile(`(\p{Greek}+)|(\p{Lu}\p{Ll}*)|([0-9]+)`)
+	s := "Ωμέγα and Zürich 42 αβ"
+	if got := re.FindAllString(s, -1); !reflect.DeepEqual(got, []string{"Ωμέγα", "Zürich", "42", "αβ"}) {
+		t.Errorf("FindAllString: got %q", got)
+	}
+	wantIndex := [][]int{{0, 10, 0, 10, -1, -1, -1, -1}, {15, 22, -1, -1, 15, 22, -1, -1}, {23, 25, -1, -1, -1, -1, 23, 25}, {26, 30, 26, 30, -1, -1, -1, -1}}
+	if got := re.FindAllStringSubmatchIndex(s, -1); !reflect.DeepEqual(got, wantIndex) {
+		t.Errorf("FindAllStringSubmatchIndex: got %v, want %v", got, wantIndex)
+	}
+
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"leftmost-first alternation", regexp.MustCompile(`a|ab`).FindString("ab"), "a"},
+		{"leftmost-longest alternation", regexp.MustCompilePOSIX(`a|ab`).FindString("ab"), "ab"},
+		{"case folding", regexp.MustCompile(`(?i)straße|ß`).FindAllString("STRASSE Straße ẞ", -1), []string{"Straße", "ẞ"}},
+		{"named groups", regexp.MustCompile(`(?P<key>\w+)=(?P<value>[^;]*)`).ReplaceAllString("a=1;bb=;c=x y", "${value}:${key}"), "1:a;:bb;x y:c"},
+		{"ASCII word boundaries", regexp.MustCompile(`\b\w`).FindAllString("héllo wörld", -1), []string{"h", "l", "w", "r"}},
+		{"runes outside the BMP", regexp.MustCompile(`.`).FindAllString("a💙b", -1), []string{"a", "💙", "b"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+type deferPoint struct{ X, Y int }
+
+func (p deferPoint) record(out *[]deferPoint) { *out = append(*out, p) }
+
+func (p *deferPoint) recordPtr(out *[]deferPoint) { *out = append(*out, *p) }
+
+type deferPair [2]int
+
+func (a deferPair) record(out *[]deferPair) { *out = append(*out, a) }
+
+type deferCount int
+
+func (n deferCount) record(out *[]deferCount) { *out = append(*out, n) }
+
+type deferOuter struct {
+	deferPoint
+	name string
+}
+
+func TestDeferValueReceiver(t *testing.T) {
+	// The receiver of a deferred method call with a value receiver is
+	// evaluated, and so copied, when the defer statement executes.
+	var points []deferPoint
+	var pairs []deferPair
+	var counts []deferCount
+	func() {
+		p := deferPoint{1, 2}
+		defer p.recordPtr(&points)
+		defer p.record(&points)
+		ptr := &p
+		defer ptr.record(&points)
+		o := deferOuter{deferPoint{5, 6}, "o"}
+		defer o.record(&points)
+		a := deferPair{1, 2}
+		defer a.record(&pairs)
+		n := deferCount(1)
+		defer n.record(&counts)
+
+		p.X, p.Y = 3, 4
+		o.X = 7
+		a[0] = 3
+		n = 2
+	}()
+	if want := []deferPoint{{5, 6}, {1, 2}, {1, 2}, {3, 4}}; !reflect.DeepEqual(points, want) {
+		t.Errorf("structs: got %v, want %v", points, want)
+	}
+	if want := []deferPair{{1, 2}}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("arrays: got %v, want %v", pairs, want)
+	}
+	if want := []deferCount{1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("named basic types: got %v, want %v", counts, want)
+	}
+
+	// Deferring in a loop copies the receiver of each iteration.
+	var looped []deferPoint
+	func() {
+		p := deferPoint{}
+		for i := 0; i < 3; i++ {
+			p.X = i
+			defer p.record(&looped)
+		}
+	}()
+	if want := []deferPoint{{2, 0}, {1, 0}, {0, 0}}; !reflect.DeepEqual(looped, want) {
+		t.Errorf("loop: got %v, want %v", looped, want)
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	// Formatting and parsing is done by the time package itself, so the
+	// results are those of native Go.
+	tm := time.Date(2009, time.November, 10, 23, 4, 5, 123456789, time.FixedZone("CET", 3600))
+	tests := []struct {
+		layout, want string
+	}{
+		{"2006-01-02 15:04:05", "2009-11-10 23:04:05"},
+		{time.RFC3339, "2009-11-10T23:04:05+01:00"},
+		{time.RFC3339Nano, "2009-11-10T23:04:05.123456789+01:00"},
+		{time.RFC1123Z, "Tue, 10 Nov 2009 23:04:05 +0100"},
+		{time.RFC850, "Tuesday, 10-Nov-09 23:04:05 CET"},
+		{time.Kitchen, "11:04PM"},
+		{time.StampMicro, "Nov 10 23:04:05.123456"},
+		{"Mon Jan _2 3:4:5 pm 2006", "Tue Nov 10 11:4:5 pm 2009"},
+		{"15:04:05.000 -07:00:00 Z0700 MST", "23:04:05.123 +01:00:00 +0100 CET"},
+		{"05.999999999", "05.123456789"},
+	}
+	for _, tt := range tests {
+		if got := tm.Format(tt.layout); got != tt.want {
+			t.Errorf("Format(%q): got %q, want %q", tt.layout, got, tt.want)
+		}
+	}
+
+	utc := time.Date(2017, time.March, 1, 2, 3, 4, 500000000, time.UTC)
+	if got, want := utc.Format(time.RFC3339Nano), "2017-03-01T02:03:04.5Z"; got != want {
+		t.Errorf("UTC: got %q, want %q", got, want)
+	}
+	if got, want := utc.In(time.FixedZone("", -(5*3600+30*60))).Format(time.RFC3339), "2017-02-28T20:33:04-05:30"; got != want {
+		t.Errorf("negative offset: got %q, want %q", got, want)
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, "2009-11-10T23:04:05.123456789+01:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(tm) {
+		t.Errorf("Parse: got %v, want %v", parsed, tm)
+	}
+	if _, offset := parsed.Zone(); offset != 3600 {
+		t.Errorf("Parse: got offset %d, want 3600", offset)
+	}
+	if _, err := time.Parse("2006-01-02", "2009-13-10"); err == nil {
+		t.Error("Parse: got no error for month 13")
+	}
+}
+
+type arrayHolder struct {
+	A    [3]int
+	Grid [2][2]string
+}
+
+func mutateArray(a [3]int) [3]int {
+	a[0] = 100
+	return a
+}
+
+func TestArrayValueSemantics(t *testing.T) {
+	a := [3]int{1, 2, 3}
+	b := a
+	b[0] = 10
+	var c [3]int
+	c = a
+	c[1] = 20
+	if a != [3]int{1, 2, 3} || b != [3]int{10, 2, 3} || c != [3]int{1, 20, 3} {
+		t.Errorf("assignment: got a %v, b %v, c %v", a, b, c)
+	}
+
+	r := mutateArray(a)
+	r[1] = 200
+	if a != [3]int{1, 2, 3} || r != [3]int{100, 200, 3} {
+		t.Errorf("argument and result: got a %v, r %v", a, r)
+	}
+
+	h := arrayHolder{A: a}
+	h.Grid[0][0] = "x"
+	h2 := h
+	h2.A[2] = 30
+	h2.Grid[0][0] = "y"
+	p := &h
+	h3 := *p
+	h3.A[0] = 0
+	if h.A != [3]int{1, 2, 3} || h.Grid[0][0] != "x" || h2.A != [3]int{1, 2, 30} || h2.Grid[0][0] != "y" || h3.A[0] != 0 {
+		t.Errorf("struct fields: got h %v, h2 %v, h3 %v", h, h2, h3)
+	}
+	field := h.A
+	field[0] = 5
+	if h.A[0] != 1 {
+		t.Errorf("field assignment: got h.A %v", h.A)
+	}
+
+	m := map[string][3]int{"a": a}
+	fromMap := m["a"]
+	fromMap[0] = 7
+	arrays := [][3]int{a}
+	arrays[0][0] = 8
+	var boxed interface{} = a
+	a[2] = 9
+	if m["a"] != [3]int{1, 2, 3} || arrays[0] != [3]int{8, 2, 3} || boxed.([3]int) != [3]int{1, 2, 3} {
+		t.Errorf("containers: got map %v, slice %v, interface %v", m["a"], arrays[0], boxed)
+	}
+
+	for i, v := range [][3]int{{1}, {2}} {
+		v[1] = i + 1
+		if v[0] != i+1 || v[1] != i+1 {
+			t.Errorf("range value %d: got %v", i, v)
+		}
+	}
+	// The range expression is copied for the values of arrays.
+	rows := [2][3]int{{1}, {2}}
+	var seen []int
+	for _, row := range rows {
+		rows[1][0] = 3
+		row[2] = 4
+		seen = append(seen, row[0])
+	}
+	if rows != [2][3]int{{1}, {3}} || len(seen) != 2 || seen[0] != 1 || seen[1] != 2 {
+		t.Errorf("range over array: got rows %v, values %v", rows, seen)
+	}
+	ints := [3]int{1, 2, 3}
+	sum := 0
+	for i, v := range ints {
+		if i == 0 {
+			ints[2] = 30
+		}
+		sum += v
+	}
+	if sum != 6 || ints[2] != 30 {
+		t.Errorf("range over array: got sum %d, ints %v", sum, ints)
+	}
+	for i := range ints {
+		if i == 0 {
+			ints[1] = 20
+		}
+	}
+	if ints != [3]int{1, 20, 30} {
+		t.Errorf("range over array keys: got %v", ints)
+	}
+}
+
+// reservedMethoder has a method whose name is reserved in JavaScript, so
+// its property is renamed.
+type reservedMethoder interface {
+	delete() string
+}
+
+type reservedImpl struct{}
+
+func (reservedImpl) delete() string { return "deleted" }
+
+var nilMethodSink interface{}
+
+func TestNilInterfaceMethods(t *testing.T) {
+	var err error
+	var r reservedMethoder
+	tests := map[string]func(){
+		"call":              func() { _ = err.Error() },
+		"reserved call":     func() { _ = r.delete() },
+		"method value":      func() { nilMethodSink = err.Error },
+		"reserved value":    func() { nilMethodSink = r.delete },
+		"method expression": func() { _ = error.Error(err) },
+		"reserved expr":     func() { _ = reservedMethoder.delete(r) },
+		"deferred":          func() { defer err.Error() },
+	}
+	for name, f := range tests {
+		var msg string
+		func() {
+			defer func() {
+				if e, ok := recover().(runtime.Error); ok {
+					msg = e.Error()
+				}
+			}()
+			f()
+		}()
+		if want := "runtime error: invalid memory address or nil pointer dereference"; msg != want {
+			t.Errorf("%s: got panic %q, want %q", name, msg, want)
+		}
+	}
+
+	r = reservedImpl{}
+	f := r.delete
+	if got := f() + reservedMethoder.delete(r); got != "deleteddeleted" {
+		t.Errorf("got %q, want %q", got, "deleteddeleted")
+	}
+}
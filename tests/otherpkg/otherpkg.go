@@ -1,3 +1,9 @@
 package otherpkg
 
 var Test float32
+
+// Answer has the same name as vendored.Answer, to test that package level
+// identifiers of different packages don't collide.
+var Answer = "otherpkg"
+
+func Name() string { return "otherpkg" }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// writer hides the methods of w other than Write, so that io.Copy calls it.
+type writer struct {
+	w io.Writer
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+func main() {
+	if _, err := io.Copy(os.Stdout, strings.NewReader("héllo, ")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if _, err := io.Copy(writer{os.Stdout}, strings.NewReader("gopher\n")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	io.WriteString(os.Stderr, "to stderr\n")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Split(bufio.ScanWords)
+	w := bufio.NewWriter(os.Stdout)
+	for n := 1; scanner.Scan(); n++ {
+		fmt.Fprintf(w, "%d: %s\n", n, scanner.Text())
+	}
+	w.Flush()
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,13 @@
+// Package entry is built with "gopherjs build --entry Serve" by TestEntry.
+package entry
+
+import "fmt"
+
+func init() {
+	fmt.Println("initialized")
+}
+
+// Serve is the entry point of the program.
+func Serve() {
+	fmt.Println("serving")
+}
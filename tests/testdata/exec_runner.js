@@ -0,0 +1,7 @@
+// Runs the script given as the first argument with the remaining arguments,
+// like Node.js would, after announcing itself, for "gopherjs run --exec".
+var path = require("path");
+
+console.log("runner");
+process.argv.splice(1, 1);
+require(path.resolve(process.argv[1]));
@@ -0,0 +1,9 @@
+// Package failing has a test that always fails, to check that it is not run
+// by "gopherjs test --count=0" in TestCountZero.
+package failing
+
+import "testing"
+
+func TestFail(t *testing.T) {
+	t.Fatal("the test was run")
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	name := flag.String("name", "world", "who to greet")
+	count := flag.Int("n", 1, "how many times")
+	flag.Parse()
+	for i := 0; i < *count; i++ {
+		fmt.Printf("hello, %s\n", *name)
+	}
+	fmt.Println(flag.Args(), len(os.Args))
+}
@@ -0,0 +1,4 @@
+// Package gen has a directive printing the environment of its generator.
+package gen
+
+//go:generate sh -c "echo $GOFILE $GOLINE $GOPACKAGE $DOLLAR{GOOS} $DOLLAR{GOARCH}"
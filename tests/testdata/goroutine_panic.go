@@ -0,0 +1,11 @@
+package main
+
+import "time"
+
+func main() {
+	go func() {
+		panic("panic in goroutine")
+	}()
+	time.Sleep(100 * time.Millisecond)
+	println("not reached")
+}
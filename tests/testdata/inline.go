@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+type point struct{ x int }
+
+func and(a, b bool) bool { return a && b }
+
+func offset(p *point, d int) int { return p.x + d }
+
+var calls []string
+
+func arg(name string) int {
+	calls = append(calls, name)
+	return len(calls)
+}
+
+func main() {
+	fmt.Println(and(arg("a") > 1, arg("b") > 1), calls)
+
+	defer func() {
+		fmt.Println(recover() != nil, calls)
+	}()
+	var p *point
+	offset(p, arg("d"))
+}
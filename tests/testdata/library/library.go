@@ -0,0 +1,18 @@
+// Package library is built with "gopherjs build --library" by TestLibrary.
+package library
+
+import "strings"
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func Greet(name string) string {
+	return "hello, " + name
+}
+
+func Fields(s string) []string {
+	return strings.Fields(s)
+}
+
+func unexported() {}
@@ -0,0 +1,9 @@
+package main
+
+import "log"
+
+func main() {
+	log.Println("starting")
+	log.Fatal("boom")
+	log.Println("not reached")
+}
@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+func recurse(n int) int {
+	return recurse(n+1) + 1
+}
+
+func main() {
+	defer func() {
+		fmt.Println("recovered:", recover())
+	}()
+	fmt.Println(recurse(0))
+}
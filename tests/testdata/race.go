@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	racy    int
+	guarded int
+	sent    int
+	mu      sync.Mutex
+)
+
+func main() {
+	// The goroutine writes racy while main sleeps, which doesn't synchronize.
+	go func() { racy = 1 }()
+	time.Sleep(10 * time.Millisecond)
+	fmt.Println(racy)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			guarded++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	done := make(chan bool)
+	go func() {
+		sent = 2
+		done <- true
+	}()
+	<-done
+	fmt.Println(guarded, sent)
+}
@@ -0,0 +1,9 @@
+package main
+
+import "github.com/gopherjs/gopherjs/js"
+
+func main() {
+	js.Global.Set("add", func(a, b int) int {
+		return a + b
+	})
+}
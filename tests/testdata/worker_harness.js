@@ -0,0 +1,16 @@
+// Loads the worker bundle given as the first argument in a fake Web Worker
+// scope, sends it some messages and prints the replies.
+var fs = require("fs");
+var vm = require("vm");
+
+var scope = vm.createContext({
+  console: console,
+  setTimeout: setTimeout,
+  clearTimeout: clearTimeout,
+  postMessage: function(msg) { console.log(JSON.stringify(msg)); }
+});
+vm.runInContext("var self = this;", scope);
+vm.runInContext(fs.readFileSync(process.argv[2], "utf8"), scope);
+
+scope.onmessage({ data: { id: 1, name: "add", args: [2, 3] } });
+scope.onmessage({ data: { id: 2, name: "missing", args: [] } });
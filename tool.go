@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -17,8 +18,10 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
@@ -71,12 +74,45 @@ func main() {
 	flagVerbose.BoolVarP(&options.Verbose, "verbose", "v", false, "print the names of packages as they are compiled")
 	flagQuiet := pflag.NewFlagSet("", 0)
 	flagQuiet.BoolVarP(&options.Quiet, "quiet", "q", false, "suppress non-fatal warnings")
+	flagSilent := pflag.NewFlagSet("", 0)
+	flagSilent.BoolVarP(&options.Silent, "silent", "s", false, "suppress all output except errors")
 
 	compilerFlags := pflag.NewFlagSet("", 0)
 	compilerFlags.BoolVarP(&options.Minify, "minify", "m", false, "minify generated code")
 	compilerFlags.BoolVar(&options.Color, "color", terminal.IsTerminal(int(os.Stderr.Fd())) && os.Getenv("TERM") != "dumb", "colored output")
 	compilerFlags.StringVar(&tags, "tags", "", "a list of build tags to consider satisfied during the build")
 	compilerFlags.BoolVar(&options.MapToLocalDisk, "localmap", false, "use local paths for sourcemap")
+	compilerFlags.BoolVarP(&options.ForceRebuild, "rebuild-all", "a", false, "force rebuilding of packages that are already up-to-date")
+	compilerFlags.BoolVar(&options.IgnoreVendor, "ignore-vendor", false, "do not resolve imports from vendor directories")
+	compilerFlags.StringVar(&options.PkgDir, "pkgdir", "", "install and load all library packages from this directory instead of the usual locations")
+	compilerFlags.Var(&overlayFlag{options: options}, "overlay", "read a JSON file with a Replace object mapping source files to the files to read instead, or to \"\" to remove them, like go build -overlay")
+	compilerFlags.StringVar(&options.StdlibCache, "stdlib-cache", "", "load standard library packages from the prebuilt archives in this directory, keyed on the GopherJS version, instead of compiling them")
+	compilerFlags.StringVar(&options.Target, "target", "", "kind of environment the output is built for; \"worker\" adds a Web Worker message handler dispatching to exported functions")
+	compilerFlags.StringVar(&options.StackTrace, "stacktrace", "", "what to print for an unrecovered panic under Node.js besides the panic value: \"full\" JavaScript stack (the default), \"go\" stack frames in Go files, which requires source maps, or \"none\"")
+	compilerFlags.BoolVar(&options.Strict, "strict", false, "put the whole output, including prepended and appended code, in strict mode")
+	compilerFlags.StringVar(&options.PrependFile, "prepend", "", "JavaScript file to insert at the top of the generated code, before the prelude")
+	compilerFlags.StringVar(&options.AppendFile, "append", "", "JavaScript file to insert at the end of the generated code, after the program")
+	compilerFlags.StringArrayVar(&options.Embed, "embed", nil, "embed the base64 encoded contents of the files matching glob into the string variable varname of the main package, given as glob=varname")
+	compilerFlags.IntVar(&options.MaxFuncSize, "max-func-size", 64*1024, "warn about functions whose generated code is larger than this many bytes, 0 to disable")
+	compilerFlags.BoolVarP(&options.AllErrors, "all-errors", "e", false, "report all errors, not just the first 10")
+	compilerFlags.Var(&langFlag{options: options}, "lang", "highest version of the Go language the sources may use, like go1.8; only alias declarations are rejected below go1.9, and newer versions than the compiler supports are invalid")
+	compilerFlags.BoolVar(&options.TraceImports, "trace-imports", false, "print the tree of imports of the built packages, to find out why a package is part of the output")
+	compilerFlags.BoolVar(&options.EmitMetadata, "emit-metadata", false, "write the exported API of the packages of a program as JSON next to the output file, with .json appended to its name")
+	compilerFlags.BoolVar(&options.Race, "race", false, "report accesses of package level variables by different goroutines without a channel operation, go statement or call of package sync or sync/atomic in between; packages in GOROOT are not instrumented")
+	compilerFlags.IntVar(&options.OptLevel, "opt", 0, "optimization level; 1 enables inlining of small functions, also across packages")
+	compilerFlags.BoolVar(&options.BuildVCS, "buildvcs", false, "set the package level variable vcsRevision of main packages that declare it without a value to the git commit of their directory, or to \"\" outside of a git repository")
+	compilerFlags.BoolVar(&options.BuildID, "buildid", false, "start the output with a comment holding a build ID, a hash of the program that is reproduced by building the same sources with the same options")
+	compilerFlags.BoolVar(&options.Verify, "verify", false, "check that the generated JavaScript parses, using node --check, to catch compiler bugs at build time")
+	compilerFlags.BoolVar(&options.AllowUnsupported, "allow-unsupported", false, "compile packages using cgo, replacing the functions that use it with stubs that panic when called, instead of failing")
+	compilerFlags.Var(&errorFormatFlag{options: options}, "errors", "how to print errors and warnings: \"text\", or \"json\" for arrays of objects with file, line, col, message and severity, for editors")
+	compilerFlags.BoolVar(&options.DumpTypes, "dumptypes", false, "print the resolved types of package level declarations of compiled packages")
+
+	flagDryRun := pflag.NewFlagSet("", 0)
+	flagDryRun.BoolVar(&options.DryRun, "dry-run", false, "print which packages are up-to-date (cached) and which would be compiled (stale), without compiling anything")
+
+	var keepGoing bool
+	flagKeepGoing := pflag.NewFlagSet("", 0)
+	flagKeepGoing.BoolVarP(&keepGoing, "keep-going", "k", false, "continue with the remaining packages after one fails, reporting all errors at the end")
 
 	flagWatch := pflag.NewFlagSet("", 0)
 	flagWatch.BoolVarP(&options.Watch, "watch", "w", false, "watch for changes to the source files")
@@ -86,12 +122,28 @@ func main() {
 		Short: "compile packages and dependencies",
 	}
 	cmdBuild.Flags().StringVarP(&pkgObj, "output", "o", "", "output file")
+	cmdBuild.Flags().BoolVar(&options.Split, "split", false, "write the output as a directory of separately cacheable files for the prelude and each package, with a manifest and a loader script for browsers; source maps are not written")
+	cmdBuild.Flags().StringVar(&options.Format, "format", "", "write commands in this format instead of a single script: \"cjs\" for a directory of CommonJS modules, one for each package, with index.js as the entry point for require; source maps are not written")
+	library := cmdBuild.Flags().String("library", "", "build a non-main package as a library exposing its exported functions as an object, which is module.exports under Node.js and the global variable of this name elsewhere")
+	entry := cmdBuild.Flags().String("entry", "", "build a non-main package as a program that calls this exported function of it, which takes no arguments, instead of main")
+	cmdBuild.Flags().StringVar(&options.BundleReport, "bundle-report", "", "write an HTML report of how many bytes each package contributes to the output to this file")
+	srcArchive := cmdBuild.Flags().String("srcarchive", "", "read package sources from this zip or tar.gz archive of a GOPATH workspace, in addition to the GOPATH")
 	cmdBuild.Flags().AddFlagSet(flagVerbose)
 	cmdBuild.Flags().AddFlagSet(flagQuiet)
+	cmdBuild.Flags().AddFlagSet(flagSilent)
 	cmdBuild.Flags().AddFlagSet(compilerFlags)
 	cmdBuild.Flags().AddFlagSet(flagWatch)
+	cmdBuild.Flags().AddFlagSet(flagDryRun)
+	cmdBuild.Flags().AddFlagSet(flagKeepGoing)
 	cmdBuild.Run = func(cmd *cobra.Command, args []string) {
 		options.BuildTags = strings.Fields(tags)
+		if *srcArchive != "" {
+			a, err := gbuild.OpenSourceArchive(*srcArchive)
+			if err != nil {
+				os.Exit(handleError(err, options, nil))
+			}
+			options.SourceArchive = a
+		}
 		for {
 			s := gbuild.NewSession(options)
 
@@ -105,7 +157,10 @@ func main() {
 					}
 					if pkgObj == "" {
 						basename := filepath.Base(args[0])
-						pkgObj = basename[:len(basename)-3] + ".js"
+						pkgObj = basename[:len(basename)-3]
+						if !options.Split && options.Format == "" {
+							pkgObj += ".js"
+						}
 					}
 					names := make([]string, len(args))
 					for i, name := range args {
@@ -124,8 +179,14 @@ func main() {
 				// Expand import path patterns.
 				patternContext := gbuild.NewBuildContext("", options.BuildTags)
 				pkgs := (&gotool.Context{BuildContext: *patternContext}).ImportPaths(args)
+				if *library != "" && len(pkgs) != 1 {
+					return fmt.Errorf("gopherjs build: --library requires a single package")
+				}
+				if *entry != "" && (len(pkgs) != 1 || *library != "") {
+					return fmt.Errorf("gopherjs build: --entry requires a single package, and can't be combined with --library")
+				}
 
-				for _, pkgPath := range pkgs {
+				buildPkg := func(pkgPath string) error {
 					if s.Watcher != nil {
 						pkg, err := gbuild.NewBuildContext(s.InstallSuffix(), options.BuildTags).Import(pkgPath, "", build.FindOnly)
 						if err != nil {
@@ -133,7 +194,7 @@ func main() {
 						}
 						s.Watcher.Add(pkg.Dir)
 					}
-					pkg, err := gbuild.Import(pkgPath, 0, s.InstallSuffix(), options.BuildTags)
+					pkg, err := s.Import(pkgPath, 0)
 					if err != nil {
 						return err
 					}
@@ -141,18 +202,42 @@ func main() {
 					if err != nil {
 						return err
 					}
+					if options.DryRun {
+						return nil
+					}
 					if len(pkgs) == 1 { // Only consider writing output if single package specified.
 						if pkgObj == "" {
-							pkgObj = filepath.Base(pkg.Dir) + ".js"
+							pkgObj = filepath.Base(pkg.Dir)
+							if !options.Split && options.Format == "" {
+								pkgObj += ".js"
+							}
+						}
+						if *library != "" {
+							if err := s.BuildLibrary(pkg, *library, pkgObj); err != nil {
+								return err
+							}
+							return nil
+						}
+						if *entry != "" {
+							if err := s.BuildEntry(pkg, *entry, pkgObj); err != nil {
+								return err
+							}
+							return nil
 						}
 						if pkg.IsCommand() && !pkg.UpToDate {
 							if err := s.WriteCommandPackage(archive, pkgObj); err != nil {
 								return err
 							}
+							if !options.Quiet {
+								if err := printBuildSummary(s, archive, pkgObj, options); err != nil {
+									return err
+								}
+							}
 						}
 					}
+					return nil
 				}
-				return nil
+				return forEachPackage(pkgs, keepGoing, buildPkg)
 			}()
 			exitCode := handleError(err, options, nil)
 
@@ -169,8 +254,13 @@ func main() {
 	}
 	cmdInstall.Flags().AddFlagSet(flagVerbose)
 	cmdInstall.Flags().AddFlagSet(flagQuiet)
+	cmdInstall.Flags().AddFlagSet(flagSilent)
 	cmdInstall.Flags().AddFlagSet(compilerFlags)
 	cmdInstall.Flags().AddFlagSet(flagWatch)
+	cmdInstall.Flags().AddFlagSet(flagDryRun)
+	cmdInstall.Flags().AddFlagSet(flagKeepGoing)
+	cmdInstall.Flags().BoolVar(&options.DocIndex, "docindex", false, "write the exported symbols of installed library packages with their doc comments as JSON next to their archives, with .index.json for the extension")
+	binDir := cmdInstall.Flags().String("bin-dir", os.Getenv("GOPHERJS_BIN"), "install commands into this directory instead of the bin directory of their GOPATH workspace (default $GOPHERJS_BIN)")
 	cmdInstall.Run = func(cmd *cobra.Command, args []string) {
 		options.BuildTags = strings.Fields(tags)
 		for {
@@ -189,27 +279,39 @@ func main() {
 						return err
 					}
 				}
-				for _, pkgPath := range pkgs {
-					pkg, err := gbuild.Import(pkgPath, 0, s.InstallSuffix(), options.BuildTags)
+				installPkg := func(pkgPath string) error {
+					pkg, err := s.Import(pkgPath, 0)
 					if s.Watcher != nil && pkg != nil { // add watch even on error
 						s.Watcher.Add(pkg.Dir)
 					}
 					if err != nil {
 						return err
 					}
+					if pkg.IsCommand() && *binDir != "" {
+						pkg.PkgObj = filepath.Join(*binDir, filepath.Base(pkg.PkgObj))
+					}
 
 					archive, err := s.BuildPackage(pkg)
 					if err != nil {
 						return err
 					}
+					if options.DryRun {
+						return nil
+					}
 
 					if pkg.IsCommand() && !pkg.UpToDate {
 						if err := s.WriteCommandPackage(archive, pkg.PkgObj); err != nil {
 							return err
 						}
+						if !options.Quiet {
+							if err := printBuildSummary(s, archive, pkg.PkgObj, options); err != nil {
+								return err
+							}
+						}
 					}
+					return nil
 				}
-				return nil
+				return forEachPackage(pkgs, keepGoing, installPkg)
 			}()
 			exitCode := handleError(err, options, nil)
 
@@ -244,13 +346,22 @@ func main() {
 	cmdGet.Run = cmdInstall.Run
 
 	cmdRun := &cobra.Command{
-		Use:   "run [gofiles...] [arguments...]",
+		Use:   "run [gofiles...|package] [arguments...]",
 		Short: "compile and run Go program",
 	}
 	cmdRun.Flags().AddFlagSet(flagVerbose)
 	cmdRun.Flags().AddFlagSet(flagQuiet)
+	cmdRun.Flags().AddFlagSet(flagSilent)
 	cmdRun.Flags().AddFlagSet(compilerFlags)
+	browser := cmdRun.Flags().Bool("browser", false, "run the program in the default web browser instead of Node.js")
+	browserPort := cmdRun.Flags().Int("browser-port", 0, "with --browser, serve the program over HTTP on this port instead of opening it from a file")
+	timeout := cmdRun.Flags().Duration("timeout", 0, "terminate the program if it runs longer than this duration, e.g. 30s (0 means no limit)")
+	runner := cmdRun.Flags().String("exec", "", "run the program with this command, e.g. \"deno run\", given the path of the compiled script and the arguments, instead of Node.js")
+	// Like for go run, flags after the program belong to the program, so that
+	// it can parse them with the flag package.
+	cmdRun.Flags().SetInterspersed(false)
 	cmdRun.Run = func(cmd *cobra.Command, args []string) {
+		options.BuildTags = strings.Fields(tags)
 		err := func() error {
 			lastSourceArg := 0
 			for {
@@ -259,8 +370,8 @@ func main() {
 				}
 				lastSourceArg++
 			}
-			if lastSourceArg == 0 {
-				return fmt.Errorf("gopherjs run: no go files listed")
+			if lastSourceArg == 0 && len(args) == 0 {
+				return fmt.Errorf("gopherjs run: no go files or package listed")
 			}
 
 			tempfile, err := ioutil.TempFile(currentDirectory, filepath.Base(args[0])+".")
@@ -276,10 +387,39 @@ func main() {
 				os.Remove(tempfile.Name() + ".map")
 			}()
 			s := gbuild.NewSession(options)
-			if err := s.BuildFiles(args[:lastSourceArg], tempfile.Name(), currentDirectory); err != nil {
+			if lastSourceArg == 0 {
+				// Handle "gopherjs run [package]" by building the whole main package.
+				pkg, err := s.Import(args[0], 0)
+				if err != nil {
+					return err
+				}
+				if !pkg.IsCommand() {
+					return fmt.Errorf("gopherjs run: cannot run non-main package %s", pkg.ImportPath)
+				}
+				archive, err := s.BuildPackage(pkg)
+				if err != nil {
+					return err
+				}
+				if err := s.WriteCommandPackage(archive, tempfile.Name()); err != nil {
+					return err
+				}
+				lastSourceArg = 1
+			} else if err := s.BuildFiles(args[:lastSourceArg], tempfile.Name(), currentDirectory); err != nil {
 				return err
 			}
-			if err := runNode(tempfile.Name(), args[lastSourceArg:], "", options.Quiet); err != nil {
+			if *browser {
+				if *runner != "" {
+					return fmt.Errorf("gopherjs run: --exec and --browser are mutually exclusive")
+				}
+				if len(args[lastSourceArg:]) != 0 {
+					return fmt.Errorf("gopherjs run: program arguments are not supported with --browser")
+				}
+				return runBrowser(tempfile.Name(), *browserPort)
+			}
+			if *runner != "" {
+				return runExec(*runner, tempfile.Name(), args[lastSourceArg:], "", *timeout)
+			}
+			if err := runNode(tempfile.Name(), args[lastSourceArg:], "", options.Quiet, *timeout); err != nil {
 				return err
 			}
 			return nil
@@ -295,24 +435,45 @@ func main() {
 	}
 	bench := cmdTest.Flags().String("bench", "", "Run benchmarks matching the regular expression. By default, no benchmarks run. To run all benchmarks, use '--bench=.'.")
 	benchtime := cmdTest.Flags().String("benchtime", "", "Run enough iterations of each benchmark to take t, specified as a time.Duration (for example, -benchtime 1h30s). The default is 1 second (1s).")
-	count := cmdTest.Flags().String("count", "", "Run each test and benchmark n times (default 1). Examples are always run once.")
+	count := cmdTest.Flags().String("count", "", "Run each test and benchmark n times (default 1). Examples are always run once. With 0, the tests are compiled, but not run.")
 	run := cmdTest.Flags().String("run", "", "Run only those tests and examples matching the regular expression.")
 	short := cmdTest.Flags().Bool("short", false, "Tell long-running tests to shorten their run time.")
 	verbose := cmdTest.Flags().BoolP("verbose", "v", false, "Log all tests as they are run. Also print all text from Log and Logf calls even if the test succeeds.")
 	compileOnly := cmdTest.Flags().BoolP("compileonly", "c", false, "Compile the test binary to pkg.test.js but do not run it (where pkg is the last element of the package's import path). The file name can be changed with the -o flag.")
 	outputFilename := cmdTest.Flags().StringP("output", "o", "", "Compile the test binary to the named file. The test still runs (unless -c is specified).")
+	cover := cmdTest.Flags().Bool("cover", false, "Enable coverage analysis, printing the percentage of the statements of each package that its tests execute.")
+	coverMode := cmdTest.Flags().String("covermode", "", "Set the mode for coverage analysis, \"set\" (the default) recording whether each statement runs, or \"count\" how many times. Implies --cover.")
+	coverProfile := cmdTest.Flags().String("coverprofile", "", "Write a coverage profile of the tested packages to the file, for go tool cover. Implies --cover. The tests write their profiles through the Node.js file system, which requires the syscall module.")
+	cmdTest.Flags().AddFlagSet(flagSilent)
 	cmdTest.Flags().AddFlagSet(compilerFlags)
 	cmdTest.Run = func(cmd *cobra.Command, args []string) {
 		options.BuildTags = strings.Fields(tags)
 		err := func() error {
+			if *coverMode == "" && (*cover || *coverProfile != "") {
+				*coverMode = "set"
+			}
+			var profile *os.File
+			if *coverProfile != "" {
+				var err error
+				profile, err = os.Create(*coverProfile)
+				if err != nil {
+					return err
+				}
+				defer profile.Close()
+				if _, err := fmt.Fprintf(profile, "mode: %s\n", *coverMode); err != nil {
+					return err
+				}
+			}
+
 			// Expand import path patterns.
 			patternContext := gbuild.NewBuildContext("", options.BuildTags)
 			args = (&gotool.Context{BuildContext: *patternContext}).ImportPaths(args)
 
+			importSession := gbuild.NewSession(options)
 			pkgs := make([]*gbuild.PackageData, len(args))
 			for i, pkgPath := range args {
 				var err error
-				pkgs[i], err = gbuild.Import(pkgPath, 0, "", options.BuildTags)
+				pkgs[i], err = importSession.Import(pkgPath, 0)
 				if err != nil {
 					return err
 				}
@@ -321,7 +482,7 @@ func main() {
 			var exitErr error
 			for _, pkg := range pkgs {
 				if len(pkg.TestGoFiles) == 0 && len(pkg.XTestGoFiles) == 0 {
-					fmt.Printf("?   \t%s\t[no test files]\n", pkg.ImportPath)
+					options.PrintInfo("?   \t%s\t[no test files]\n", pkg.ImportPath)
 					continue
 				}
 				s := gbuild.NewSession(options)
@@ -348,18 +509,21 @@ func main() {
 					return nil
 				}
 
-				if err := collectTests(&gbuild.PackageData{
+				testPkg := &gbuild.PackageData{
 					Package: &build.Package{
 						ImportPath: pkg.ImportPath,
 						Dir:        pkg.Dir,
 						GoFiles:    append(pkg.GoFiles, pkg.TestGoFiles...),
 						Imports:    append(pkg.Imports, pkg.TestImports...),
 					},
-					IsTest:  true,
-					JSFiles: pkg.JSFiles,
-				}, "_test", &tests.NeedTest); err != nil {
+					IsTest:    true,
+					JSFiles:   pkg.JSFiles,
+					CoverMode: *coverMode,
+				}
+				if err := collectTests(testPkg, "_test", &tests.NeedTest); err != nil {
 					return err
 				}
+				tests.CoverMode, tests.CoverFiles = testPkg.CoverMode, testPkg.CoverFiles
 
 				if err := collectTests(&gbuild.PackageData{
 					Package: &build.Package{
@@ -393,7 +557,7 @@ func main() {
 						return s.BuildImportPath(path)
 					},
 				}
-				mainPkgArchive, err := compiler.Compile("main", []*ast.File{mainFile}, fset, importContext, options.Minify)
+				mainPkgArchive, err := compiler.Compile("main", []*ast.File{mainFile}, fset, importContext, options.Minify, options.OptLevel, options.Race)
 				if err != nil {
 					return err
 				}
@@ -429,6 +593,11 @@ func main() {
 				if *compileOnly {
 					continue
 				}
+				if n, err := strconv.Atoi(*count); err == nil && n == 0 {
+					// With --count=0, checking that the tests compile is all.
+					options.PrintInfo("ok  \t%s\t[no tests run]\n", pkg.ImportPath)
+					continue
+				}
 
 				var args []string
 				if *bench != "" {
@@ -449,16 +618,33 @@ func main() {
 				if *verbose {
 					args = append(args, "-test.v")
 				}
-				status := "ok  "
+				var pkgProfile string
+				if profile != nil && len(tests.CoverFiles) != 0 {
+					f, err := ioutil.TempFile("", "gopherjs-cover.")
+					if err != nil {
+						return err
+					}
+					f.Close()
+					pkgProfile = f.Name()
+					defer os.Remove(pkgProfile)
+					args = append(args, "-test.coverprofile", pkgProfile)
+				}
 				start := time.Now()
-				if err := runNode(outfile.Name(), args, pkg.Dir, options.Quiet); err != nil {
+				err = runNode(outfile.Name(), args, pkg.Dir, options.Quiet, 0)
+				if pkgProfile != "" {
+					if err := appendCoverProfile(profile, pkgProfile); err != nil {
+						return err
+					}
+				}
+				if err != nil {
 					if _, ok := err.(*exec.ExitError); !ok {
 						return err
 					}
 					exitErr = err
-					status = "FAIL"
+					fmt.Printf("FAIL\t%s\t%.3fs\n", pkg.ImportPath, time.Since(start).Seconds())
+					continue
 				}
-				fmt.Printf("%s\t%s\t%.3fs\n", status, pkg.ImportPath, time.Since(start).Seconds())
+				options.PrintInfo("ok  \t%s\t%.3fs\n", pkg.ImportPath, time.Since(start).Seconds())
 			}
 			return exitErr
 		}()
@@ -467,12 +653,98 @@ func main() {
 		os.Exit(exitCode)
 	}
 
+	cmdPrecompile := &cobra.Command{
+		Use:   "precompile [packages]",
+		Short: "compile library packages into the package object cache ahead of time",
+	}
+	cmdPrecompile.Flags().AddFlagSet(flagVerbose)
+	cmdPrecompile.Flags().AddFlagSet(flagQuiet)
+	cmdPrecompile.Flags().AddFlagSet(flagSilent)
+	cmdPrecompile.Flags().AddFlagSet(compilerFlags)
+	cmdPrecompile.Run = func(cmd *cobra.Command, args []string) {
+		options.BuildTags = strings.Fields(tags)
+		err := func() error {
+			s := gbuild.NewSession(options)
+
+			// Expand import path patterns.
+			patternContext := gbuild.NewBuildContext("", options.BuildTags)
+			pkgs := (&gotool.Context{BuildContext: *patternContext}).ImportPaths(args)
+
+			for _, pkgPath := range pkgs {
+				// BuildImportPath writes the package objects of the package and all its dependencies.
+				if _, err := s.BuildImportPath(pkgPath); err != nil {
+					return err
+				}
+			}
+			return nil
+		}()
+		exitCode := handleError(err, options, nil)
+
+		os.Exit(exitCode)
+	}
+
+	cmdGenerate := &cobra.Command{
+		Use:   "generate [packages]",
+		Short: "generate Go files by processing source",
+	}
+	cmdGenerate.Flags().AddFlagSet(flagVerbose)
+	cmdGenerate.Flags().StringVar(&tags, "tags", "", "a list of build tags to consider satisfied during the build")
+	generateRun := cmdGenerate.Flags().String("run", "", "Run only those directives whose full original source text matches the regular expression.")
+	cmdGenerate.Run = func(cmd *cobra.Command, args []string) {
+		options.BuildTags = strings.Fields(tags)
+		err := func() error {
+			var runRegexp *regexp.Regexp
+			if *generateRun != "" {
+				var err error
+				runRegexp, err = regexp.Compile(*generateRun)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Expand import path patterns.
+			patternContext := gbuild.NewBuildContext("", options.BuildTags)
+			pkgs := (&gotool.Context{BuildContext: *patternContext}).ImportPaths(args)
+
+			s := gbuild.NewSession(options)
+			for _, pkgPath := range pkgs {
+				pkg, err := s.Import(pkgPath, 0)
+				if err != nil {
+					return err
+				}
+				if err := runGenerate(pkg, runRegexp, options.Verbose); err != nil {
+					return err
+				}
+			}
+			return nil
+		}()
+		exitCode := handleError(err, options, nil)
+
+		os.Exit(exitCode)
+	}
+
+	cmdRepl := &cobra.Command{
+		Use:   "repl",
+		Short: "evaluate Go declarations, statements and expressions interactively",
+		Long:  "Repl reads Go source from standard input. Declarations, imports and short variable declarations are remembered; statements are run and the values of expressions are printed. Each input is compiled together with the remembered declarations into a fresh program that is run with Node.js, so package level initializers are evaluated anew every time.",
+	}
+	cmdRepl.Flags().AddFlagSet(flagQuiet)
+	cmdRepl.Flags().AddFlagSet(compilerFlags)
+	cmdRepl.Run = func(cmd *cobra.Command, args []string) {
+		options.BuildTags = strings.Fields(tags)
+		err := runRepl(os.Stdin, options)
+		exitCode := handleError(err, options, nil)
+
+		os.Exit(exitCode)
+	}
+
 	cmdServe := &cobra.Command{
 		Use:   "serve [root]",
 		Short: "compile on-the-fly and serve",
 	}
 	cmdServe.Flags().AddFlagSet(flagVerbose)
 	cmdServe.Flags().AddFlagSet(flagQuiet)
+	cmdServe.Flags().AddFlagSet(flagSilent)
 	cmdServe.Flags().AddFlagSet(compilerFlags)
 	var addr string
 	cmdServe.Flags().StringVarP(&addr, "http", "", ":8080", "HTTP bind address to serve")
@@ -527,7 +799,7 @@ func main() {
 		Use:  "gopherjs",
 		Long: "GopherJS is a tool for compiling Go source code to JavaScript.",
 	}
-	rootCmd.AddCommand(cmdBuild, cmdGet, cmdInstall, cmdRun, cmdTest, cmdServe, cmdVersion, cmdDoc)
+	rootCmd.AddCommand(cmdBuild, cmdGet, cmdInstall, cmdRun, cmdTest, cmdGenerate, cmdPrecompile, cmdRepl, cmdServe, cmdVersion, cmdDoc)
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(2)
@@ -572,7 +844,7 @@ func (fs serveCommandFileSystem) Open(requestName string) (http.File, error) {
 	if isPkg || isMap || isIndex {
 		// If we're going to be serving our special files, make sure there's a Go command in this folder.
 		s := gbuild.NewSession(fs.options)
-		pkg, err := gbuild.Import(path.Dir(name), 0, s.InstallSuffix(), fs.options.BuildTags)
+		pkg, err := s.Import(path.Dir(name), 0)
 		if err != nil || pkg.Name != "main" {
 			isPkg = false
 			isMap = false
@@ -597,7 +869,7 @@ func (fs serveCommandFileSystem) Open(requestName string) (http.File, error) {
 				if err != nil {
 					return err
 				}
-				if err := compiler.WriteProgramCode(deps, sourceMapFilter); err != nil {
+				if err := s.WriteProgramCode(deps, sourceMapFilter); err != nil {
 					return err
 				}
 
@@ -693,11 +965,15 @@ func (f *fakeFile) Sys() interface{} {
 // handleError handles err and returns an appropriate exit code.
 // If browserErrors is non-nil, errors are written for presentation in browser.
 func handleError(err error, options *gbuild.Options, browserErrors *bytes.Buffer) int {
+	if _, isExit := err.(*exec.ExitError); err != nil && !isExit && options.ErrorFormat == "json" {
+		options.PrintJSONErrors(options.ErrorList(err))
+		return 1
+	}
 	switch err := err.(type) {
 	case nil:
 		return 0
 	case compiler.ErrorList:
-		for _, entry := range err {
+		for _, entry := range options.ErrorList(err) {
 			printError(entry, options, browserErrors)
 		}
 		return 1
@@ -738,11 +1014,177 @@ func sprintError(err error) string {
 	}
 }
 
-func runNode(script string, args []string, dir string, quiet bool) error {
+// errorFormatFlag is the value of the --errors flag, "text" or "json", which
+// is stored as the ErrorFormat of options.
+type errorFormatFlag struct {
+	options *gbuild.Options
+}
+
+func (f *errorFormatFlag) Type() string { return "format" }
+
+func (f *errorFormatFlag) String() string {
+	if f.options == nil || f.options.ErrorFormat == "" {
+		return "text"
+	}
+	return f.options.ErrorFormat
+}
+
+func (f *errorFormatFlag) Set(s string) error {
+	if s != "text" && s != "json" {
+		return fmt.Errorf("unknown error format %q, must be text or json", s)
+	}
+	f.options.ErrorFormat = s
+	return nil
+}
+
+// langFlag is the value of the --lang flag, which is checked when it is set.
+type langFlag struct {
+	options *gbuild.Options
+}
+
+func (f *langFlag) Type() string { return "version" }
+
+func (f *langFlag) String() string {
+	if f.options == nil {
+		return ""
+	}
+	return f.options.Lang
+}
+
+func (f *langFlag) Set(s string) error {
+	if err := gbuild.CheckLang(s); err != nil {
+		return err
+	}
+	f.options.Lang = s
+	return nil
+}
+
+// overlayFlag is the value of the --overlay flag. The file is read into the
+// Overlay of options when the flag is set.
+type overlayFlag struct {
+	options *gbuild.Options
+	name    string
+}
+
+func (f *overlayFlag) String() string { return f.name }
+func (f *overlayFlag) Type() string   { return "file" }
+
+func (f *overlayFlag) Set(name string) error {
+	overlay, err := gbuild.ReadOverlay(name)
+	if err != nil {
+		return err
+	}
+	f.name = name
+	f.options.Overlay = overlay
+	return nil
+}
+
+// forEachPackage calls fn for each of the import paths pkgs, stopping at the
+// first error, unless keepGoing is set, in which case the remaining packages
+// are built as well and the errors of all of them are returned. Errors of a
+// dependency shared by several failing packages are only reported once.
+func forEachPackage(pkgs []string, keepGoing bool, fn func(pkgPath string) error) error {
+	var errs compiler.ErrorList
+	seen := make(map[string]bool)
+	for _, pkgPath := range pkgs {
+		err := fn(pkgPath)
+		if err == nil {
+			continue
+		}
+		if !keepGoing {
+			return err
+		}
+		list, ok := err.(compiler.ErrorList)
+		if !ok {
+			list = compiler.ErrorList{err}
+		}
+		for _, e := range list {
+			if !seen[e.Error()] {
+				seen[e.Error()] = true
+				errs = append(errs, e)
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// printBuildSummary prints the size of the written command package pkgObj and the
+// number of packages linked into it to Stderr. With options.Verbose, the size of the
+// generated code of each package is listed as well.
+func printBuildSummary(s *gbuild.Session, archive *compiler.Archive, pkgObj string, options *gbuild.Options) error {
+	var size int64
+	err := filepath.Walk(pkgObj, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	deps, err := compiler.ImportDependencies(archive, func(path string) (*compiler.Archive, error) {
+		if archive, ok := s.Archives[path]; ok {
+			return archive, nil
+		}
+		return nil, fmt.Errorf("package %s was not built", path)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "wrote %s (%s, %d packages)\n", pkgObj, formatSize(size), len(deps))
+	if options.Verbose {
+		for _, dep := range deps {
+			size := len(dep.IncJSCode)
+			for _, d := range dep.Declarations {
+				size += len(d.DeclCode) + len(d.MethodListCode) + len(d.TypeInitCode) + len(d.InitCode)
+			}
+			fmt.Fprintf(os.Stderr, "  %s (%s)\n", dep.ImportPath, formatSize(int64(size)))
+		}
+	}
+	return nil
+}
+
+// formatSize returns n bytes in a human readable form.
+func formatSize(n int64) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%d KB", n/1024)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// nodeKillDelay is how long runCommand waits for Node.js or another runtime to
+// exit after asking it to terminate, before killing it.
+const nodeKillDelay = 5 * time.Second
+
+// nodeVersionChecked is set once runNode has checked the version of Node.js.
+var nodeVersionChecked bool
+
+// runNode runs script with Node.js. If timeout is non-zero and the script runs
+// longer than that, Node.js is terminated and an error is returned.
+func runNode(script string, args []string, dir string, quiet bool, timeout time.Duration) error {
+	nodeExe, err := gbuild.NodeExecutable()
+	if err != nil {
+		return err
+	}
+	if !quiet && !nodeVersionChecked {
+		nodeVersionChecked = true
+		if major, err := gbuild.NodeVersion(nodeExe); err == nil && major < gbuild.MinNodeVersion {
+			fmt.Fprintf(os.Stderr, "gopherjs: Node.js %d is too old, programs may fail. Install Node.js %d.x or newer.\n", major, gbuild.MinNodeVersion)
+		}
+	}
+
 	var allArgs []string
 	if b, _ := strconv.ParseBool(os.Getenv("SOURCE_MAP_SUPPORT")); os.Getenv("SOURCE_MAP_SUPPORT") == "" || b {
 		allArgs = []string{"--require", "source-map-support/register"}
-		if err := exec.Command("node", "--require", "source-map-support/register", "--eval", "").Run(); err != nil {
+		if err := exec.Command(nodeExe, "--require", "source-map-support/register", "--eval", "").Run(); err != nil {
 			if !quiet {
 				fmt.Fprintln(os.Stderr, "gopherjs: Source maps disabled. Install source-map-support module for nice stack traces. See https://github.com/gopherjs/gopherjs#gopherjs-run-gopherjs-test.")
 			}
@@ -776,18 +1218,498 @@ func runNode(script string, args []string, dir string, quiet bool) error {
 	allArgs = append(allArgs, script)
 	allArgs = append(allArgs, args...)
 
-	node := exec.Command("node", allArgs...)
-	node.Dir = dir
-	node.Stdin = os.Stdin
-	node.Stdout = os.Stdout
-	node.Stderr = os.Stderr
-	err := node.Run()
+	return runCommand(exec.Command(nodeExe, allArgs...), "Node.js", dir, timeout)
+}
+
+// runExec runs script with the command runner, like "deno run", which is split
+// into words and given the path of script followed by args, like the -exec
+// flag of go run.
+func runExec(runner, script string, args []string, dir string, timeout time.Duration) error {
+	words := strings.Fields(runner)
+	if len(words) == 0 {
+		return fmt.Errorf("gopherjs run: empty --exec command")
+	}
+	allArgs := append(words[1:], script)
+	allArgs = append(allArgs, args...)
+	return runCommand(exec.Command(words[0], allArgs...), words[0], dir, timeout)
+}
+
+// runCommand runs cmd, a JavaScript runtime called name in errors, in dir with
+// the standard streams of gopherjs. If timeout is non-zero and cmd runs longer
+// than that, it is terminated and an error is returned.
+func runCommand(cmd *exec.Cmd, name, dir string, timeout time.Duration) error {
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not run %s: %s", name, err.Error())
+	}
+	timedOut := make(chan bool, 1)
+	if timeout != 0 {
+		timer := time.AfterFunc(timeout, func() {
+			timedOut <- true
+			if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+				cmd.Process.Kill() // SIGTERM is not supported on Windows.
+				return
+			}
+			time.Sleep(nodeKillDelay)
+			cmd.Process.Kill()
+		})
+		defer timer.Stop()
+	}
+	err := cmd.Wait()
+	select {
+	case <-timedOut:
+		return fmt.Errorf("gopherjs run: program timed out after %v", timeout)
+	default:
+	}
 	if _, ok := err.(*exec.ExitError); err != nil && !ok {
-		err = fmt.Errorf("could not run Node.js: %s", err.Error())
+		err = fmt.Errorf("could not run %s: %s", name, err.Error())
 	}
 	return err
 }
 
+// browserHarness is the HTML page that loads a program run with "gopherjs run --browser".
+const browserHarness = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+</head>
+<body>
+<script src="%[1]s"></script>
+</body>
+</html>
+`
+
+// runBrowser runs the compiled script in a web browser. If port is non-zero, the
+// script is served over HTTP on that port of the loopback interface, otherwise
+// it is written to a temporary directory together with an HTML page that is
+// opened in the default browser. Either way, it returns once interrupted, after
+// which the page is no longer available.
+func runBrowser(script string, port int) error {
+	name := filepath.Base(script)
+	code, err := ioutil.ReadFile(script)
+	if err != nil {
+		return err
+	}
+	sourceMap, err := ioutil.ReadFile(script + ".map")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	index := fmt.Sprintf(browserHarness, name)
+
+	if port != 0 {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/", "/index.html":
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Write([]byte(index))
+			case "/" + name:
+				w.Header().Set("Content-Type", "application/javascript")
+				w.Write(code)
+			case "/" + name + ".map":
+				if sourceMap == nil {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write(sourceMap)
+			default:
+				http.NotFound(w, r)
+			}
+		})
+		ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			return err
+		}
+		defer ln.Close()
+		interrupt := interrupted()
+		served := make(chan error, 1)
+		go func() { served <- http.Serve(ln, mux) }()
+		fmt.Printf("serving at http://localhost:%d\n", port)
+		select {
+		case err := <-served:
+			return err
+		case <-interrupt:
+			return nil
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "gopherjs-run.")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, name), code, 0666); err != nil {
+		return err
+	}
+	if sourceMap != nil {
+		if err := ioutil.WriteFile(filepath.Join(dir, name+".map"), sourceMap, 0666); err != nil {
+			return err
+		}
+	}
+	indexFile := filepath.Join(dir, "index.html")
+	if err := ioutil.WriteFile(indexFile, []byte(index), 0666); err != nil {
+		return err
+	}
+
+	var open *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		open = exec.Command("open", indexFile)
+	case "windows":
+		open = exec.Command("cmd", "/c", "start", "", indexFile)
+	default:
+		open = exec.Command("xdg-open", indexFile)
+	}
+	interrupt := interrupted()
+	if err := open.Run(); err != nil {
+		fmt.Printf("open %s in a web browser to run the program\n", indexFile)
+	}
+	fmt.Println("press Ctrl+C to exit once the program has run")
+	<-interrupt
+	return nil
+}
+
+// interrupted returns a channel that receives the interrupt signal, which no
+// longer terminates the process.
+func interrupted() <-chan os.Signal {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	return c
+}
+
+// replState holds the imports and package level declarations accumulated by
+// "gopherjs repl".
+type replState struct {
+	imports []string // import specs, e.g. `"strings"` or `m "math"`
+	decls   []string
+}
+
+// source returns a main package made of the accumulated state, the additional
+// declarations decls and a main function with the given body. Imports that
+// are not referenced are left out, so that they don't fail the build.
+func (r *replState) source(decls []string, body string) string {
+	allDecls := append(append([]string(nil), r.decls...), decls...)
+	rest := strings.Join(allDecls, "\n") + "\nfunc main() {\n" + body + "\n}\n"
+
+	used := make(map[string]bool)
+	if f, err := parser.ParseFile(token.NewFileSet(), "", "package main\n"+rest, 0); err == nil {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok {
+					used[id.Name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("package main\n\nimport __fmt \"fmt\"\n\nvar _ = __fmt.Println\n")
+	for _, spec := range r.imports {
+		if name, _ := replImportName(spec); used[name] {
+			fmt.Fprintf(&buf, "import %s\n", spec)
+		}
+	}
+	buf.WriteString(rest)
+	return buf.String()
+}
+
+// replImportName returns the name under which the import spec is visible, and
+// its import path.
+func replImportName(spec string) (name, path string) {
+	fields := strings.Fields(spec)
+	path, _ = strconv.Unquote(fields[len(fields)-1])
+	if len(fields) == 2 {
+		return fields[0], path
+	}
+	return filepath.Base(path), path
+}
+
+// replInputComplete reports whether src has no unclosed brackets, so that
+// multi-line declarations can be entered.
+func replInputComplete(src string) bool {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", -1, len(src)), []byte(src), nil, 0)
+	depth := 0
+	for {
+		_, tok, _ := s.Scan()
+		switch tok {
+		case token.EOF:
+			return depth <= 0
+		case token.LBRACE, token.LPAREN, token.LBRACK:
+			depth++
+		case token.RBRACE, token.RPAREN, token.RBRACK:
+			depth--
+		}
+	}
+}
+
+// runRepl implements "gopherjs repl", reading input from in until EOF.
+func runRepl(in io.Reader, options *gbuild.Options) error {
+	dir, err := ioutil.TempDir("", "gopherjs-repl")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	mainFile := filepath.Join(dir, "main.go")
+	script := filepath.Join(dir, "main.js")
+
+	s := gbuild.NewSession(options)
+	state := &replState{}
+
+	// build compiles src into script. Compile errors are printed, and
+	// reported by returning false.
+	build := func(src string, printErrors bool) bool {
+		if err := ioutil.WriteFile(mainFile, []byte(src), 0644); err != nil {
+			options.PrintError("%s\n", err)
+			return false
+		}
+		delete(s.Archives, "main")
+		delete(s.Types, "main")
+		err := s.BuildFiles([]string{mainFile}, script, dir)
+		if err == nil {
+			return true
+		}
+		if printErrors {
+			for _, entry := range options.ErrorList(err) {
+				// Positions refer to the synthesized program, so leave them out.
+				switch entry := entry.(type) {
+				case types.Error:
+					options.PrintError("%s\n", entry.Msg)
+				case *scanner.Error:
+					options.PrintError("%s\n", entry.Msg)
+				default:
+					options.PrintError("%s\n", entry)
+				}
+			}
+		}
+		return false
+	}
+	run := func() {
+		if err := runNode(script, nil, dir, true, 0); err != nil {
+			if _, ok := err.(*exec.ExitError); !ok {
+				options.PrintError("%s\n", err)
+			}
+		}
+	}
+
+	scan := bufio.NewScanner(in)
+	prompt := "> "
+	var input string
+	for {
+		fmt.Print(prompt)
+		if !scan.Scan() {
+			fmt.Println()
+			return scan.Err()
+		}
+		input += scan.Text() + "\n"
+		if !replInputComplete(input) {
+			prompt = "... "
+			continue
+		}
+		line := strings.TrimSpace(input)
+		input, prompt = "", "> "
+		if line == "" {
+			continue
+		}
+
+		switch strings.Fields(line)[0] {
+		case "import":
+			f, err := parser.ParseFile(token.NewFileSet(), "", "package main\n"+line, parser.ImportsOnly)
+			if err != nil {
+				options.PrintError("%s\n", err)
+				continue
+			}
+			var specs []string
+			for _, imp := range f.Imports {
+				path, _ := strconv.Unquote(imp.Path.Value)
+				if _, err := gbuild.Import(path, 0, s.InstallSuffix(), options.BuildTags); err != nil {
+					options.PrintError("%s\n", err)
+					specs = nil
+					break
+				}
+				spec := imp.Path.Value
+				if imp.Name != nil {
+					spec = imp.Name.Name + " " + spec
+				}
+				specs = append(specs, spec)
+			}
+			state.imports = append(state.imports, specs...)
+			continue
+		case "func", "type", "var", "const":
+			if build(state.source([]string{line}, ""), true) {
+				state.decls = append(state.decls, line)
+			}
+			continue
+		}
+
+		if _, err := parser.ParseExpr(line); err == nil {
+			if build(state.source(nil, "__fmt.Println("+line+")"), false) {
+				run()
+				continue
+			}
+			// Calls of functions without results can't be printed; run them as statements.
+		}
+		if decl, ok := replShortVarDecl(line); ok {
+			if build(state.source([]string{decl}, ""), true) {
+				state.decls = append(state.decls, decl)
+			}
+			continue
+		}
+		if build(state.source(nil, line), true) {
+			run()
+		}
+	}
+}
+
+// replShortVarDecl turns the short variable declaration stmt into the
+// equivalent package level var declaration, so that the variables remain
+// visible to later input.
+func replShortVarDecl(stmt string) (string, bool) {
+	src := "package main\nfunc _() {\n" + stmt + "\n}\n"
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return "", false
+	}
+	body := f.Decls[0].(*ast.FuncDecl).Body.List
+	if len(body) != 1 {
+		return "", false
+	}
+	assign, ok := body[0].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE {
+		return "", false
+	}
+	var names []string
+	for _, lhs := range assign.Lhs {
+		id, ok := lhs.(*ast.Ident)
+		if !ok {
+			return "", false
+		}
+		names = append(names, id.Name)
+	}
+	// Offsets are 1-based positions into src.
+	rhs := src[assign.Rhs[0].Pos()-1 : assign.Rhs[len(assign.Rhs)-1].End()-1]
+	return "var " + strings.Join(names, ", ") + " = " + rhs, true
+}
+
+// runGenerate executes the //go:generate directives found in the Go files of pkg,
+// in file and line order. If runRegexp is non-nil, only directives whose source
+// text matches it are executed.
+func runGenerate(pkg *gbuild.PackageData, runRegexp *regexp.Regexp, verbose bool) error {
+	var files []string
+	files = append(files, pkg.GoFiles...)
+	files = append(files, pkg.TestGoFiles...)
+	files = append(files, pkg.XTestGoFiles...)
+	sort.Strings(files)
+
+	fileSet := token.NewFileSet()
+	for _, name := range files {
+		filename := filepath.Join(pkg.Dir, name)
+		if verbose {
+			fmt.Fprintln(os.Stderr, filename)
+		}
+		f, err := parser.ParseFile(fileSet, filename, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, group := range f.Comments {
+			for _, comment := range group.List {
+				if !strings.HasPrefix(comment.Text, "//go:generate ") {
+					continue
+				}
+				pos := fileSet.Position(comment.Slash)
+				if pos.Column != 1 {
+					continue // directives must start at the beginning of the line
+				}
+				if runRegexp != nil && !runRegexp.MatchString(comment.Text) {
+					continue
+				}
+
+				env := []string{
+					"GOARCH=js",
+					"GOOS=" + gbuild.NewBuildContext("", nil).GOOS,
+					"GOFILE=" + name,
+					"GOLINE=" + strconv.Itoa(pos.Line),
+					"GOPACKAGE=" + f.Name.Name,
+					"DOLLAR=$",
+				}
+				words, err := splitGenerateDirective(comment.Text[len("//go:generate "):])
+				if err != nil {
+					return fmt.Errorf("%s:%d: %s", filename, pos.Line, err)
+				}
+				for i, word := range words {
+					words[i] = os.Expand(word, func(key string) string {
+						for _, kv := range env {
+							if strings.HasPrefix(kv, key+"=") {
+								return kv[len(key)+1:]
+							}
+						}
+						return os.Getenv(key)
+					})
+				}
+				if len(words) == 0 {
+					return fmt.Errorf("%s:%d: no arguments to directive", filename, pos.Line)
+				}
+				if verbose {
+					fmt.Fprintln(os.Stderr, strings.Join(words, " "))
+				}
+
+				generator := exec.Command(words[0], words[1:]...)
+				generator.Dir = pkg.Dir
+				generator.Stdin = os.Stdin
+				generator.Stdout = os.Stdout
+				generator.Stderr = os.Stderr
+				generator.Env = append(os.Environ(), env...)
+				if err := generator.Run(); err != nil {
+					return fmt.Errorf("%s:%d: running %q: %s", filename, pos.Line, words[0], err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// splitGenerateDirective splits the arguments of a //go:generate directive into
+// words. Double-quoted strings are unquoted and kept as a single word.
+func splitGenerateDirective(line string) ([]string, error) {
+	var words []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return words, nil
+		}
+		if line[0] != '"' {
+			end := strings.IndexAny(line, " \t")
+			if end == -1 {
+				end = len(line)
+			}
+			words = append(words, line[:end])
+			line = line[end:]
+			continue
+		}
+		end := 1
+		for ; end < len(line) && line[end] != '"'; end++ {
+			if line[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(line) {
+			return nil, fmt.Errorf("unterminated quoted string")
+		}
+		word, err := strconv.Unquote(line[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+		line = line[end+1:]
+	}
+}
+
 type testFuncs struct {
 	Tests       []testFunc
 	Benchmarks  []testFunc
@@ -798,6 +1720,8 @@ type testFuncs struct {
 	NeedTest    bool
 	ImportXtest bool
 	NeedXtest   bool
+	CoverMode   string
+	CoverFiles  []gbuild.CoverFile
 }
 
 type testFunc struct {
@@ -807,6 +1731,20 @@ type testFunc struct {
 	Unordered bool   // output is allowed to be unordered.
 }
 
+// appendCoverProfile appends the blocks of the coverage profile written by the
+// test of a package, name, to profile, without its mode line.
+func appendCoverProfile(profile *os.File, name string) error {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if i := bytes.IndexByte(data, '\n'); bytes.HasPrefix(data, []byte("mode:")) && i != -1 {
+		data = data[i+1:]
+	}
+	_, err = profile.Write(data)
+	return err
+}
+
 var testFileSet = token.NewFileSet()
 
 func (t *testFuncs) load(filename, pkg string, doImport, seen *bool) error {
@@ -914,6 +1852,9 @@ import (
 {{if .ImportXtest}}
 	{{if .NeedXtest}}_xtest{{else}}_{{end}} {{.Package.ImportPath | printf "%s_test" | printf "%q"}}
 {{end}}
+{{if .CoverFiles}}
+	_cover {{.Package.ImportPath | printf "%q"}}
+{{end}}
 )
 
 var tests = []testing.InternalTest{
@@ -934,6 +1875,28 @@ var examples = []testing.InternalExample{
 {{end}}
 }
 
+{{if .CoverFiles}}
+func init() {
+	testing.RegisterCover(testing.Cover{
+		Mode: {{.CoverMode | printf "%q"}},
+		Counters: map[string][]uint32{
+{{range .CoverFiles}}
+			{{.Name | printf "%q"}}: _cover.{{.Var}}.Count[:],
+{{end}}
+		},
+		Blocks: map[string][]testing.CoverBlock{
+{{range .CoverFiles}}
+			{{.Name | printf "%q"}}: {
+{{range .Blocks}}
+				{ {{.Line0}}, {{.Col0}}, {{.Line1}}, {{.Col1}}, {{.Stmts}} },
+{{end}}
+			},
+{{end}}
+		},
+	})
+}
+{{end}}
+
 func main() {
 	m := testing.MainStart(testdeps.TestDeps{}, tests, benchmarks, examples)
 {{with .TestMain}}